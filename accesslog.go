@@ -0,0 +1,244 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/teal-finance/garcon/gg"
+)
+
+// Access log fields selectable in NewAccessLogger.
+// Any other field name is considered as an HTTP header name (e.g. "User-Agent").
+const (
+	FieldIP          = "ip"
+	FieldMethod      = "method"
+	FieldRoute       = "route"
+	FieldStatus      = "status"
+	FieldBytes       = "bytes"
+	FieldDuration    = "duration"
+	FieldTTFB        = "ttfb" // time-to-first-byte
+	FieldRequestID   = "request_id"
+	FieldTraceID     = "trace_id"
+	FieldFingerprint = "fingerprint" // all the fingerprint headers, see FingerprintExplanation
+)
+
+// Access log formats supported by NewAccessLogHandler.
+const (
+	FormatJSON   = "json"
+	FormatLogfmt = "logfmt"
+	FormatText   = "text"
+)
+
+// DefaultAccessLogFields is used when no fields are provided to NewAccessLogger.
+func DefaultAccessLogFields() []string {
+	return []string{FieldIP, FieldMethod, FieldRoute, FieldStatus, FieldBytes, FieldDuration}
+}
+
+// fingerprintHeaders are the headers logged by fingerprint().
+func fingerprintHeaders() []string {
+	return []string{
+		"Accept-Language", "User-Agent", "Referer", "Accept", "Accept-Encoding",
+		"Connection", "Cache-Control", "Upgrade-Insecure-Requests", "Via",
+		"Authorization", "Cookie", "DNT",
+	}
+}
+
+// AccessLogger emits one structured record per request through a log/slog handler.
+type AccessLogger struct {
+	logger *slog.Logger
+	// Sampler reduces the number of logged requests, nil logs all requests.
+	Sampler *LogSampler
+	fields  []string
+	headers []string
+}
+
+// MiddlewareAccessLog logs the requests in JSON, logfmt or text format on the standard output.
+// See NewAccessLogger for the fields.
+// To log into a file, use gg.NewRotatingFile with NewAccessLogHandler.
+func (g *Garcon) MiddlewareAccessLog(format string, fields ...string) gg.Middleware {
+	al := NewAccessLogger(NewAccessLogHandler(format, os.Stdout), fields...)
+	al.Sampler = g.logSampler
+	return g.withPrivacy(al.Middleware)
+}
+
+// NewAccessLogger creates an AccessLogger using the slog handler (see NewAccessLogHandler).
+// The fields are selected among the Field* constants. The other field names are HTTP headers.
+// In absence of fields, AccessLogger uses DefaultAccessLogFields().
+func NewAccessLogger(h slog.Handler, fields ...string) *AccessLogger {
+	if h == nil {
+		log.Panic("garcon.NewAccessLogger() requires a slog.Handler, see NewAccessLogHandler()")
+	}
+	if len(fields) == 0 {
+		fields = DefaultAccessLogFields()
+	}
+
+	al := &AccessLogger{
+		logger:  slog.New(h),
+		Sampler: nil,
+		fields:  make([]string, 0, len(fields)),
+		headers: nil,
+	}
+
+	for _, f := range fields {
+		switch f {
+		case FieldIP, FieldMethod, FieldRoute, FieldStatus, FieldBytes, FieldDuration, FieldTTFB, FieldRequestID, FieldTraceID:
+			al.fields = append(al.fields, f)
+		case FieldFingerprint:
+			al.headers = append(al.headers, fingerprintHeaders()...)
+		case "":
+			log.Panic("garcon.NewAccessLogger() does not accept an empty field name")
+		default:
+			al.headers = append(al.headers, http.CanonicalHeaderKey(f))
+		}
+	}
+
+	al.headers = gg.Deduplicate(al.headers)
+	return al
+}
+
+// NewAccessLogHandler returns a slog.Handler writing to w using the format:
+//   - "json"   one JSON object per line (slog.JSONHandler),
+//   - "logfmt" one line of key=value pairs (slog.TextHandler),
+//   - "text"   human-readable line through the Garcon logger (w is ignored).
+func NewAccessLogHandler(format string, w io.Writer) slog.Handler {
+	switch format {
+	case FormatJSON:
+		return slog.NewJSONHandler(w, nil)
+	case FormatLogfmt:
+		return slog.NewTextHandler(w, nil)
+	case FormatText:
+		return emoHandler{attrs: nil}
+	default:
+		log.Panicf(`garcon.NewAccessLogHandler() accepts only "json", "logfmt" and "text" but got: %q`, format)
+		return nil
+	}
+}
+
+// Middleware logs one record per request when the response is completed.
+func (al *AccessLogger) Middleware(next http.Handler) http.Handler {
+	log.Info("MiddlewareAccessLog fields:", al.fields, "headers:", al.headers)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		record := newResponseRecorder(w)
+		next.ServeHTTP(record, r)
+		d := record.Duration()
+		if al.Sampler.Keep(record.StatusCode, d) {
+			al.log(r, record, d)
+		}
+	})
+}
+
+func (al *AccessLogger) log(r *http.Request, record *responseRecorder, d time.Duration) {
+	level := slog.LevelInfo
+	switch {
+	case record.StatusCode >= http.StatusInternalServerError:
+		level = slog.LevelError
+	case record.StatusCode >= http.StatusBadRequest:
+		level = slog.LevelWarn
+	}
+
+	ctx := r.Context()
+	if !al.logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := make([]slog.Attr, 0, len(al.fields)+len(al.headers))
+	for _, f := range al.fields {
+		switch f {
+		case FieldIP:
+			attrs = append(attrs, slog.String(f, remoteAddr(r)))
+		case FieldMethod:
+			attrs = append(attrs, slog.String(f, r.Method))
+		case FieldRoute:
+			attrs = append(attrs, slog.String(f, route(r)))
+		case FieldStatus:
+			attrs = append(attrs, slog.Int(f, record.StatusCode))
+		case FieldBytes:
+			attrs = append(attrs, slog.Int64(f, record.Bytes))
+		case FieldDuration:
+			attrs = append(attrs, slog.Duration(f, d))
+		case FieldTTFB:
+			attrs = append(attrs, slog.Duration(f, record.TTFB()))
+		case FieldRequestID:
+			if id := gg.RequestIDFromCtx(ctx); id != "" {
+				attrs = append(attrs, slog.String(f, id))
+			}
+		case FieldTraceID:
+			if id := TraceIDFromCtx(ctx); id != "" {
+				attrs = append(attrs, slog.String(f, id))
+			}
+		}
+	}
+
+	for _, h := range al.headers {
+		if v := safeHeader(r, h); v != "" {
+			attrs = append(attrs, slog.String(h, v))
+		}
+	}
+
+	al.logger.LogAttrs(ctx, level, "access", attrs...)
+}
+
+// route returns the route pattern when the router is chi
+// (the middleware must be registered with chi's Use), else the sanitized URL path.
+func route(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+	return gg.Sanitize(r.URL.Path)
+}
+
+// emoHandler is a slog.Handler printing the records through the Garcon logger.
+type emoHandler struct {
+	attrs []slog.Attr
+}
+
+func (emoHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h emoHandler) Handle(_ context.Context, record slog.Record) error {
+	var sb strings.Builder
+	sb.WriteString(record.Message)
+
+	write := func(a slog.Attr) bool {
+		sb.WriteByte(' ')
+		sb.WriteString(a.Key)
+		sb.WriteByte('=')
+		sb.WriteString(a.Value.String())
+		return true
+	}
+
+	for _, a := range h.attrs {
+		write(a)
+	}
+	record.Attrs(write)
+
+	switch {
+	case record.Level >= slog.LevelError:
+		log.Error(sb.String())
+	case record.Level >= slog.LevelWarn:
+		log.Warning(sb.String())
+	default:
+		log.Out(sb.String())
+	}
+	return nil
+}
+
+func (h emoHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return emoHandler{attrs: append(append([]slog.Attr{}, h.attrs...), attrs...)}
+}
+
+func (h emoHandler) WithGroup(string) slog.Handler { return h }
@@ -0,0 +1,59 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/teal-finance/garcon"
+)
+
+func TestAccessLogger(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		format string
+		fields []string
+		want   []string
+	}{
+		{"json-default", garcon.FormatJSON, nil, []string{`"ip":"192.0.2.1:1234"`, `"method":"GET"`, `"route":"/items"`, `"status":404`, `"bytes":9`, `"duration":`, `"level":"WARN"`}},
+		{"json-header", garcon.FormatJSON, []string{"status", "user-agent"}, []string{`"status":404`, `"User-Agent":"curl/8"`}},
+		{"logfmt", garcon.FormatLogfmt, []string{"method", "route", "status"}, []string{"method=GET", "route=/items", "status=404"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			al := garcon.NewAccessLogger(garcon.NewAccessLogHandler(c.format, &buf), c.fields...)
+			h := al.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte("not found"))
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/items", http.NoBody)
+			r.Header.Set("User-Agent", "curl/8")
+			h.ServeHTTP(httptest.NewRecorder(), r)
+
+			line := buf.String()
+			if c.format == garcon.FormatJSON && !json.Valid(buf.Bytes()) {
+				t.Fatal("invalid JSON:", line)
+			}
+			for _, w := range c.want {
+				if !strings.Contains(line, w) {
+					t.Errorf("missing %s in %s", w, line)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,328 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/teal-finance/garcon/gg"
+)
+
+// Default settings of the ErrorAlerter.
+const (
+	defaultAlertRemind = 100
+	maxAlertRoutes     = 1000
+	maxAlertStackBytes = 3000
+	otherRoutes        = "(other routes)"
+)
+
+// ErrorAlerter recovers the panics and watches the 5xx rate per route.
+// ErrorAlerter notifies the panics (with the stack trace and the request fingerprint)
+// and the 5xx bursts: when a route answers Threshold 5xx responses within the Window.
+// The repeated alerts for the same route are muted using a Muter,
+// and a notification is sent when the route is back to normal.
+//
+// The panics are notified as a gg.Message (see gg.NotifyMessage) of severity gg.SeverityCritical.
+// The other messages start with "[ERROR]" or "[INFO]"
+// to be routed by gg.RouterNotifier (see gg.ParseSeverity).
+// Notify is called within the request handling:
+// use an asynchronous notifier (see gg.AsyncNotifier) to not delay the responses.
+type ErrorAlerter struct {
+	notifier gg.Notifier
+	routes   map[string]*routeErrors
+	panics   map[string]*routePanics
+
+	// Writer writes the "500 Internal Server Error" response after a panic.
+	Writer Writer
+
+	// Route returns the route of the request (default: chi route pattern or URL path).
+	Route func(*http.Request) string
+
+	// Threshold is the number of 5xx responses within Window triggering an alert.
+	Threshold int
+
+	// Window is the period counting the 5xx responses,
+	// and also the period to detect the routes back to normal.
+	Window time.Duration
+
+	mu   sync.Mutex
+	once sync.Once
+}
+
+type routeErrors struct {
+	windowStart time.Time
+	last5xx     time.Time
+	count       int // 5xx responses within the current window
+	muter       *Muter
+}
+
+type routePanics struct {
+	lastPanic time.Time
+	muter     *Muter
+}
+
+// NewErrorAlerter creates an ErrorAlerter using the Garcon Writer.
+func (g *Garcon) NewErrorAlerter(notifier gg.Notifier, threshold int, window time.Duration) *ErrorAlerter {
+	return NewErrorAlerter(g.Writer, notifier, threshold, window)
+}
+
+// MiddlewareErrorAlert recovers the panics and notifies the panics and the 5xx bursts.
+func (g *Garcon) MiddlewareErrorAlert(notifier gg.Notifier, threshold int, window time.Duration) gg.Middleware {
+	return g.withPrivacy(g.NewErrorAlerter(notifier, threshold, window).Middleware)
+}
+
+// NewErrorAlerter creates an ErrorAlerter notifying when a route
+// answers threshold 5xx responses within the window.
+func NewErrorAlerter(gw Writer, notifier gg.Notifier, threshold int, window time.Duration) *ErrorAlerter {
+	if notifier == nil {
+		log.Panic("garcon.NewErrorAlerter() requires a Notifier")
+	}
+	if threshold < 1 || window <= 0 {
+		log.Panicf("garcon.NewErrorAlerter() requires threshold>0 and window>0, but got threshold=%d window=%v", threshold, window)
+	}
+
+	return &ErrorAlerter{
+		notifier:  notifier,
+		routes:    make(map[string]*routeErrors),
+		panics:    make(map[string]*routePanics),
+		Writer:    gw,
+		Route:     route,
+		Threshold: threshold,
+		Window:    window,
+		mu:        sync.Mutex{},
+		once:      sync.Once{},
+	}
+}
+
+// Middleware recovers the panics, answers "500 Internal Server Error" (if nothing has been sent yet)
+// and notifies the panics and the 5xx bursts.
+// The panic http.ErrAbortHandler is propagated to abort the response silently.
+// When the response headers are already sent, Middleware panics with http.ErrAbortHandler
+// (after the notification): the server aborts the connection
+// and the client does not take the truncated response as complete.
+func (a *ErrorAlerter) Middleware(next http.Handler) http.Handler {
+	log.Infof("MiddlewareErrorAlert notifies the panics and the bursts of %d 5xx within %v", a.Threshold, a.Window)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.once.Do(func() { go a.checkPeriodically(a.Window) })
+
+		record := newResponseRecorder(w)
+
+		defer func() {
+			v := recover()
+			if v == nil {
+				if record.StatusCode >= http.StatusInternalServerError {
+					a.count5xx(r)
+				}
+				return
+			}
+
+			if err, ok := v.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(v)
+			}
+
+			stack := debug.Stack()
+			log.Errorf("panic: %v %s\n%s", v, ipMethodURLSafe(r), stack)
+
+			headerSent := record.wroteHeader
+			if !headerSent {
+				a.Writer.WriteErr(record, r, http.StatusInternalServerError, ErrInternal)
+			}
+
+			a.alertPanic(r, v, stack)
+			a.count5xx(r)
+
+			if headerSent {
+				panic(http.ErrAbortHandler)
+			}
+		}()
+
+		next.ServeHTTP(record, r)
+	})
+}
+
+func (a *ErrorAlerter) alertPanic(r *http.Request, v any, stack []byte) {
+	rt := a.Route(r)
+	ok, dropped := a.panicMuter(rt).Increment()
+	if !ok {
+		return
+	}
+
+	if len(stack) > maxAlertStackBytes {
+		stack = append(stack[:maxAlertStackBytes:maxAlertStackBytes], "\n..."...)
+	}
+
+	msg := gg.Message{
+		Title:    "Panic on " + gg.Sanitize(r.Method) + " " + rt,
+		Body:     gg.Sanitize(fmt.Sprint(v)) + mutedNotice(dropped),
+		Code:     string(stack),
+		Fields:   FingerprintFields(r),
+		Links:    nil,
+		Severity: gg.SeverityCritical,
+	}
+
+	if err := gg.NotifyMessage(a.notifier, msg); err != nil {
+		log.Warn("ErrorAlerter:", err)
+	}
+}
+
+// count5xx counts the 5xx response and notifies when the Threshold is reached within the Window.
+func (a *ErrorAlerter) count5xx(r *http.Request) {
+	rt := a.Route(r)
+	now := time.Now()
+
+	a.mu.Lock()
+	re := a.routeErrors(rt, now)
+	if now.Sub(re.windowStart) >= a.Window {
+		re.windowStart = now
+		re.count = 0
+	}
+	re.count++
+	re.last5xx = now
+	crossed := (re.count == a.Threshold)
+	a.mu.Unlock()
+
+	if !crossed {
+		return
+	}
+
+	ok, dropped := re.muter.Increment()
+	if !ok {
+		return
+	}
+
+	a.notify("[ERROR] " + strconv.Itoa(a.Threshold) + " server errors (5xx) within " +
+		a.Window.String() + " on " + rt + mutedNotice(dropped))
+}
+
+// routeErrors returns the counters of the route, a.mu must be locked.
+// The number of routes is limited to prevent memory exhaustion.
+func (a *ErrorAlerter) routeErrors(rt string, now time.Time) *routeErrors {
+	re, ok := a.routes[rt]
+	if ok {
+		return re
+	}
+
+	if len(a.routes) >= maxAlertRoutes {
+		rt = otherRoutes
+		if re, ok = a.routes[rt]; ok {
+			return re
+		}
+	}
+
+	re = &routeErrors{
+		windowStart: now,
+		last5xx:     now,
+		count:       0,
+		muter: &Muter{
+			Threshold:       1,
+			NoAlertDuration: a.Window,
+			RemindMuteState: defaultAlertRemind,
+		},
+	}
+	a.routes[rt] = re
+	return re
+}
+
+// panicMuter returns the Muter of the panics of the route and records the panic time.
+// The panics are muted apart from the 5xx counters (routes):
+// they do not use the route slots and are not notified back to normal.
+func (a *ErrorAlerter) panicMuter(rt string) *Muter {
+	now := time.Now()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	rp, ok := a.panics[rt]
+	if !ok && len(a.panics) >= maxAlertRoutes {
+		rt = otherRoutes
+		rp, ok = a.panics[rt]
+	}
+	if !ok {
+		rp = &routePanics{
+			lastPanic: now,
+			muter: &Muter{
+				Threshold:       1,
+				NoAlertDuration: a.Window,
+				RemindMuteState: defaultAlertRemind,
+			},
+		}
+		a.panics[rt] = rp
+	}
+
+	rp.lastPanic = now
+	return rp.muter
+}
+
+// Check decrements the Muter of the routes without 5xx during the last Window,
+// notifies the routes back to normal and forgets the idle routes.
+// The panic muters are decremented and forgotten silently.
+// Check is called every Window.
+func (a *ErrorAlerter) Check() {
+	var messages []string
+
+	a.mu.Lock()
+	for rt, re := range a.routes {
+		if time.Since(re.last5xx) < a.Window {
+			continue // errors in progress
+		}
+		if !re.muter.Muted() {
+			delete(a.routes, rt)
+			continue
+		}
+		if ok, _, dropped := re.muter.Decrement(); ok {
+			messages = append(messages, "[INFO] Back to normal on "+rt+" ("+strconv.Itoa(dropped)+" alerts muted)")
+			delete(a.routes, rt)
+		}
+	}
+	for rt, rp := range a.panics {
+		if time.Since(rp.lastPanic) < a.Window {
+			continue
+		}
+		if !rp.muter.Muted() {
+			delete(a.panics, rt)
+			continue
+		}
+		if ok, _, _ := rp.muter.Decrement(); ok {
+			delete(a.panics, rt)
+		}
+	}
+	a.mu.Unlock()
+
+	for _, msg := range messages {
+		a.notify(msg)
+	}
+}
+
+func (a *ErrorAlerter) checkPeriodically(interval time.Duration) {
+	for range time.NewTicker(interval).C {
+		a.Check()
+	}
+}
+
+// mutedNotice informs about the muted state returned by Muter.Increment.
+func mutedNotice(dropped int) string {
+	switch {
+	case dropped == 1:
+		return "\n\n" + "(the next similar alerts are muted)"
+	case dropped > 1:
+		return "\n\n" + "(alerts still muted: " + strconv.Itoa(dropped) + " similar alerts dropped)"
+	default:
+		return ""
+	}
+}
+
+func (a *ErrorAlerter) notify(msg string) {
+	if err := a.notifier.Notify(msg); err != nil {
+		log.Warn("ErrorAlerter:", err)
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/teal-finance/garcon"
+	"github.com/teal-finance/garcon/gg"
+)
+
+// syncNotifier is a recordNotifier safe for concurrent use.
+type syncNotifier struct {
+	messages []string
+	mu       sync.Mutex
+}
+
+func (n *syncNotifier) Notify(msg string) error {
+	n.mu.Lock()
+	n.messages = append(n.messages, msg)
+	n.mu.Unlock()
+	return nil
+}
+
+func (n *syncNotifier) count(prefix string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	c := 0
+	for _, m := range n.messages {
+		if strings.HasPrefix(m, prefix) {
+			c++
+		}
+	}
+	return c
+}
+
+func TestErrorAlerter_Panic(t *testing.T) {
+	t.Parallel()
+
+	rec := &syncNotifier{}
+	a := garcon.NewErrorAlerter("https://example.com/doc", rec, 10, time.Hour)
+	h := a.Middleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	}))
+
+	for range 3 {
+		r := httptest.NewRequest(http.MethodGet, "/api/orders", nil)
+		r.Header.Set("User-Agent", "test-agent")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("status=%d want 500", w.Code)
+		}
+		if !strings.Contains(w.Body.String(), "Internal Server Error") {
+			t.Errorf("body=%s", w.Body.String())
+		}
+	}
+
+	// first panic + second panic with the muting notice, then muted
+	if n := rec.count("🚨 Panic on GET /api/orders"); n != 2 {
+		t.Fatalf("got %d panic alerts, want 2: %q", n, rec.messages)
+	}
+	msg := rec.messages[0]
+	for _, want := range []string{"boom", "alerting_test.go", "- IP: ", "- User-Agent: test-agent"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("missing %q in %q", want, msg)
+		}
+	}
+	if !strings.Contains(rec.messages[1], "the next similar alerts are muted") {
+		t.Errorf("missing muting notice in %q", rec.messages[1])
+	}
+}
+
+func TestErrorAlerter_AbortHandler(t *testing.T) {
+	t.Parallel()
+
+	rec := &syncNotifier{}
+	h := garcon.NewErrorAlerter("", rec, 1, time.Hour).Middleware(
+		http.HandlerFunc(func(http.ResponseWriter, *http.Request) { panic(http.ErrAbortHandler) }))
+
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Errorf("recover()=%v want http.ErrAbortHandler", v)
+		}
+		if len(rec.messages) > 0 {
+			t.Errorf("unexpected alert %q", rec.messages)
+		}
+	}()
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestErrorAlerter_PanicAfterHeader(t *testing.T) {
+	t.Parallel()
+
+	rec := &syncNotifier{}
+	h := garcon.NewErrorAlerter("", rec, 10, time.Hour).Middleware(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"partial":`))
+			panic("boom")
+		}))
+
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Errorf("recover()=%v want http.ErrAbortHandler to abort the truncated response", v)
+		}
+		if n := rec.count("🚨 Panic on GET"); n != 1 {
+			t.Errorf("got %d panic alerts, want 1: %q", n, rec.messages)
+		}
+	}()
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestErrorAlerter_Burst(t *testing.T) {
+	t.Parallel()
+
+	const window = 30 * time.Millisecond
+
+	rec := &syncNotifier{}
+	h := garcon.NewErrorAlerter("", rec, 2, window).Middleware(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/ok" {
+				return
+			}
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+
+	// 2xx are not counted
+	for range 10 {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+	}
+
+	// continuous errors during 4 windows
+	for end := time.Now().Add(4 * window); time.Now().Before(end); {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/prices", nil))
+		time.Sleep(window / 6)
+	}
+
+	if n := rec.count("[ERROR] 2 server errors (5xx) within 30ms on /api/prices"); n != 2 {
+		t.Errorf("got %d burst alerts, want 2 (then muted): %q", n, rec.messages)
+	}
+
+	deadline := time.Now().Add(20 * window)
+	for rec.count("[INFO] Back to normal on /api/prices") == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("missing the back-to-normal notification: %q", rec.messages)
+		}
+		time.Sleep(window / 3)
+	}
+}
+
+// messageNotifier records the structured messages (see gg.MessageNotifier).
+type messageNotifier struct {
+	syncNotifier
+	structured []gg.Message
+}
+
+func (n *messageNotifier) NotifyMessage(m gg.Message) error {
+	n.mu.Lock()
+	n.structured = append(n.structured, m)
+	n.mu.Unlock()
+	return nil
+}
+
+func TestErrorAlerter_PanicMessage(t *testing.T) {
+	t.Parallel()
+
+	const window = 30 * time.Millisecond
+
+	rec := &messageNotifier{}
+	h := garcon.NewErrorAlerter("", rec, 10, window).Middleware(
+		http.HandlerFunc(func(http.ResponseWriter, *http.Request) { panic("boom") }))
+
+	for range 3 {
+		r := httptest.NewRequest(http.MethodGet, "/api/orders", nil)
+		r.Header.Set("User-Agent", "test-agent")
+		h.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	time.Sleep(6 * window) // let Check forget the panic muter
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	if len(rec.structured) != 2 {
+		t.Fatalf("got %d panic alerts, want 2 (then muted): %+v", len(rec.structured), rec.structured)
+	}
+	m := rec.structured[0]
+	if m.Severity != gg.SeverityCritical || m.Title != "Panic on GET /api/orders" || m.Body != "boom" {
+		t.Errorf("unexpected message %+v", m)
+	}
+	if !strings.Contains(m.Code, "alerting_test.go") {
+		t.Errorf("missing the stack trace in the code block %q", m.Code)
+	}
+	if !slices.Contains(m.Fields, gg.Field{Name: "User-Agent", Value: "test-agent"}) {
+		t.Errorf("missing the User-Agent field in %+v", m.Fields)
+	}
+
+	// the panic muter is not a 5xx route: no "Back to normal" notification
+	if len(rec.messages) > 0 {
+		t.Errorf("unexpected plain messages %q", rec.messages)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/teal-finance/garcon/gg"
+)
+
+// NewAsyncNotifier creates a gg.AsyncNotifier and exports its metrics to Prometheus.
+// The name distinguishes the metrics of several AsyncNotifiers.
+// See gg.NewAsyncNotifier for the other parameters.
+func (g *Garcon) NewAsyncNotifier(name string, notifier gg.Notifier, queueSize int, spoolDir string) *gg.AsyncNotifier {
+	a := gg.NewAsyncNotifier(notifier, queueSize, spoolDir)
+	prometheus.MustRegister(AsyncNotifierCollector(g.ServerName, name, a))
+	return a
+}
+
+type asyncNotifierCollector struct {
+	notifier *gg.AsyncNotifier
+	depth    *prometheus.Desc
+	spooled  *prometheus.Desc
+	sent     *prometheus.Desc
+	failed   *prometheus.Desc
+	retries  *prometheus.Desc
+	dropped  *prometheus.Desc
+}
+
+// AsyncNotifierCollector returns a prometheus.Collector exporting
+// the queue depth, the spooled messages and the delivery counters.
+func AsyncNotifierCollector(namespace ServerName, name string, a *gg.AsyncNotifier) prometheus.Collector {
+	labels := prometheus.Labels{"notifier": name}
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(string(namespace), "notifier", metric), help, nil, labels)
+	}
+
+	return &asyncNotifierCollector{
+		notifier: a,
+		depth:    desc("queue_depth", "Number of messages waiting within the queue."),
+		spooled:  desc("spooled", "Number of undelivered messages stored on disk."),
+		sent:     desc("sent_total", "Number of delivered messages."),
+		failed:   desc("failures_total", "Number of messages undelivered after all retries."),
+		retries:  desc("retries_total", "Number of retried delivery attempts."),
+		dropped:  desc("dropped_total", "Number of lost messages (queue full without spool)."),
+	}
+}
+
+func (c *asyncNotifierCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.depth
+	ch <- c.spooled
+	ch <- c.sent
+	ch <- c.failed
+	ch <- c.retries
+	ch <- c.dropped
+}
+
+func (c *asyncNotifierCollector) Collect(ch chan<- prometheus.Metric) {
+	st := c.notifier.Stats()
+	ch <- prometheus.MustNewConstMetric(c.depth, prometheus.GaugeValue, float64(st.Depth))
+	ch <- prometheus.MustNewConstMetric(c.spooled, prometheus.GaugeValue, float64(st.Spooled))
+	ch <- prometheus.MustNewConstMetric(c.sent, prometheus.CounterValue, float64(st.Sent))
+	ch <- prometheus.MustNewConstMetric(c.failed, prometheus.CounterValue, float64(st.Failed))
+	ch <- prometheus.MustNewConstMetric(c.retries, prometheus.CounterValue, float64(st.Retries))
+	ch <- prometheus.MustNewConstMetric(c.dropped, prometheus.CounterValue, float64(st.Dropped))
+}
@@ -0,0 +1,40 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/teal-finance/garcon"
+	"github.com/teal-finance/garcon/gg"
+)
+
+func TestAsyncNotifierCollector(t *testing.T) {
+	t.Parallel()
+
+	a := gg.NewAsyncNotifier(gg.NewLogNotifier(), 10, "")
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	_ = a.Notify("dropped because closed")
+
+	c := garcon.AsyncNotifierCollector("test", "unit", a)
+
+	want := `
+# HELP test_notifier_dropped_total Number of lost messages (queue full without spool).
+# TYPE test_notifier_dropped_total counter
+test_notifier_dropped_total{notifier="unit"} 1
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(want), "test_notifier_dropped_total"); err != nil {
+		t.Error(err)
+	}
+	if n := testutil.CollectAndCount(c); n != 6 {
+		t.Errorf("collected %d metrics, want 6", n)
+	}
+}
@@ -0,0 +1,314 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/teal-finance/garcon/timex"
+)
+
+const (
+	defaultMuteDuration = time.Hour
+	maxBotRequestBytes  = 64 << 10
+)
+
+// Silencer is implemented by the Muter-backed alerting (MutedNotifier, MuterSet)
+// to be silenced by the bot command "/mute".
+type Silencer interface {
+	Silence(d time.Duration)
+}
+
+// BotCommand processes the arguments of a chat command and returns the reply.
+type BotCommand func(args []string) string
+
+// Bot processes the ops commands received from Telegram or Mattermost:
+//
+//	/status         version, uptime, maintenance/drain states and probe results
+//	/mute 1h        silence the alerts for one hour ("/mute off" to end the silence)
+//	/ready off      make the "/ready" endpoint fail (drain state), "/ready on" to restore
+//	/help           list the commands
+//
+// The Telegram webhook and the Mattermost slash command are mounted
+// on the router with TelegramHandler and MattermostHandler.
+type Bot struct {
+	commands map[string]botCommand
+	start    time.Time
+
+	// Maintenance is toggled by "/ready on|off" (nil disables this command).
+	Maintenance *Maintenance
+
+	// Silencers are silenced by "/mute".
+	Silencers []Silencer
+
+	// Probes are run by "/status" (see WithLivenessProbes).
+	Probes []ProbeFunction
+
+	mu sync.Mutex
+}
+
+type botCommand struct {
+	run  BotCommand
+	help string
+}
+
+// NewBot creates a Bot with the default commands.
+func (g *Garcon) NewBot(m *Maintenance, silencers ...Silencer) *Bot {
+	return NewBot(m, silencers...)
+}
+
+// NewBot creates a Bot with the default commands: status, mute, ready and help.
+// The Maintenance may be nil.
+func NewBot(m *Maintenance, silencers ...Silencer) *Bot {
+	b := &Bot{
+		commands:    make(map[string]botCommand),
+		start:       time.Now(),
+		Maintenance: m,
+		Silencers:   silencers,
+		Probes:      nil,
+		mu:          sync.Mutex{},
+	}
+
+	b.Handle("status", "version, uptime and probe results", b.status)
+	b.Handle("mute", "[duration|off] silence the alerts (default 1h)", b.mute)
+	b.Handle("ready", "on|off toggle the readiness endpoint", b.ready)
+	b.Handle("help", "list the commands", b.help)
+
+	return b
+}
+
+// Handle adds (or replaces) a command.
+func (b *Bot) Handle(name, help string, cmd BotCommand) {
+	b.mu.Lock()
+	b.commands[strings.ToLower(name)] = botCommand{run: cmd, help: help}
+	b.mu.Unlock()
+}
+
+// Run executes the command line such as "/mute 30m" and returns the reply.
+// The bot suffix of the Telegram group commands ("/status@my_bot") is ignored.
+func (b *Bot) Run(line string) string {
+	words := strings.Fields(line)
+	if len(words) == 0 {
+		return b.help(nil)
+	}
+
+	name := strings.ToLower(strings.TrimPrefix(words[0], "/"))
+	name, _, _ = strings.Cut(name, "@")
+
+	b.mu.Lock()
+	cmd, ok := b.commands[name]
+	b.mu.Unlock()
+
+	if !ok {
+		return "Unknown command /" + name + "\n\n" + b.help(nil)
+	}
+
+	log.State("Bot command: /" + name + " " + strings.Join(words[1:], " "))
+	return cmd.run(words[1:])
+}
+
+func (b *Bot) status([]string) string {
+	lines := []string{
+		"Version: " + info.Version,
+		"Uptime: " + timex.DStr(time.Since(b.start)),
+	}
+
+	if b.Maintenance != nil {
+		lines = append(lines,
+			"Maintenance: "+onOff(b.Maintenance.Enabled()),
+			"Ready: "+onOff(!b.Maintenance.Draining()))
+	}
+
+	failed := 0
+	for i, p := range b.Probes {
+		if txt := p(); len(txt) > 0 {
+			failed++
+			lines = append(lines, "Probe #"+strconv.Itoa(i+1)+" failed: "+string(txt))
+		}
+	}
+	if len(b.Probes) > 0 {
+		lines = append(lines, "Probes: "+strconv.Itoa(len(b.Probes)-failed)+"/"+strconv.Itoa(len(b.Probes))+" OK")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (b *Bot) mute(args []string) string {
+	if len(b.Silencers) == 0 {
+		return "No alerting to mute"
+	}
+
+	d := defaultMuteDuration
+	if len(args) > 0 {
+		if args[0] == "off" {
+			d = 0
+		} else {
+			var err error
+			d, err = time.ParseDuration(args[0])
+			if err != nil || d < 0 {
+				return "Invalid duration " + strconv.Quote(args[0]) + ", examples: /mute 30m, /mute 2h, /mute off"
+			}
+		}
+	}
+
+	for _, s := range b.Silencers {
+		s.Silence(d)
+	}
+
+	if d == 0 {
+		return "Alerts unmuted"
+	}
+	return "Alerts muted for " + timex.DStr(d) + " (until " + time.Now().Add(d).Format(time.DateTime) + ")"
+}
+
+func (b *Bot) ready(args []string) string {
+	if b.Maintenance == nil {
+		return "No Maintenance configured"
+	}
+
+	switch {
+	case len(args) == 0:
+	case args[0] == "on":
+		b.Maintenance.Drain(false)
+	case args[0] == "off":
+		b.Maintenance.Drain(true)
+	default:
+		return "Usage: /ready on|off"
+	}
+
+	return "Ready: " + onOff(!b.Maintenance.Draining())
+}
+
+func (b *Bot) help([]string) string {
+	b.mu.Lock()
+	lines := make([]string, 0, len(b.commands))
+	for name, cmd := range b.commands {
+		lines = append(lines, "/"+name+" "+cmd.help)
+	}
+	b.mu.Unlock()
+
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
+
+// TelegramHandler processes the Telegram webhook updates (see setWebhook in the Telegram Bot API).
+// Only the messages from the chatIDs are processed (see gg.TelegramNotifier.ChatID).
+// The secretToken is the "secret_token" set with setWebhook,
+// checked from the header "X-Telegram-Bot-Api-Secret-Token":
+// without it, anyone knowing a chat ID could forge the updates.
+// The reply is sent within the webhook response (method sendMessage).
+func (b *Bot) TelegramHandler(secretToken string, chatIDs ...string) http.Handler {
+	if secretToken == "" {
+		log.Panic("Bot.TelegramHandler() requires the webhook secret token")
+	}
+	if len(chatIDs) == 0 {
+		log.Panic("Bot.TelegramHandler() requires at least one authorized chat ID")
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := r.Header.Get("X-Telegram-Bot-Api-Secret-Token")
+		if subtle.ConstantTimeCompare([]byte(got), []byte(secretToken)) != 1 {
+			log.Security("Bot: invalid Telegram secret token", ipMethodURLSafe(r))
+			WriteErr(w, r, http.StatusUnauthorized, "Invalid secret token")
+			return
+		}
+
+		var update struct {
+			Message struct {
+				Text string `json:"text"`
+				Chat struct {
+					ID int64 `json:"id"`
+				} `json:"chat"`
+			} `json:"message"`
+		}
+		err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBotRequestBytes)).Decode(&update)
+		if err != nil {
+			WriteErr(w, r, http.StatusBadRequest, "Cannot decode the Telegram update")
+			return
+		}
+
+		chatID := strconv.FormatInt(update.Message.Chat.ID, 10)
+		if !slices.Contains(chatIDs, chatID) {
+			log.Security("Bot: unauthorized Telegram chat_id=" + chatID + " " + ipMethodURLSafe(r))
+			w.WriteHeader(http.StatusOK) // acknowledge to stop the Telegram retries
+			return
+		}
+
+		if !strings.HasPrefix(update.Message.Text, "/") {
+			w.WriteHeader(http.StatusOK) // not a command
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"method":  "sendMessage",
+			"chat_id": chatID,
+			"text":    b.Run(update.Message.Text),
+		})
+	})
+}
+
+// MattermostHandler processes the Mattermost slash commands.
+// The token is the one generated by Mattermost when creating the slash command.
+// A single slash command (e.g. "/ops status") or one slash command
+// per bot command (e.g. "/status") can be configured.
+// The reply is only visible by the user (ephemeral).
+func (b *Bot) MattermostHandler(token string) http.Handler {
+	if token == "" {
+		log.Panic("Bot.MattermostHandler() requires the slash command token")
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxBotRequestBytes)
+		if err := r.ParseForm(); err != nil {
+			WriteErr(w, r, http.StatusBadRequest, "Cannot parse the slash command")
+			return
+		}
+
+		got := r.PostForm.Get("token")
+		if got == "" {
+			got = strings.TrimPrefix(r.Header.Get("Authorization"), "Token ")
+		}
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			log.Security("Bot: invalid Mattermost token", ipMethodURLSafe(r))
+			WriteErr(w, r, http.StatusUnauthorized, "Invalid slash command token")
+			return
+		}
+
+		line := r.PostForm.Get("command") + " " + r.PostForm.Get("text")
+		if !b.known(r.PostForm.Get("command")) {
+			line = r.PostForm.Get("text") // e.g. "/ops status"
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"response_type": "ephemeral",
+			"text":          b.Run(line),
+		})
+	})
+}
+
+func (b *Bot) known(command string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, ok := b.commands[strings.ToLower(strings.TrimPrefix(command, "/"))]
+	return ok
+}
@@ -0,0 +1,175 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/teal-finance/garcon"
+)
+
+type recordSilencer struct{ d time.Duration }
+
+func (s *recordSilencer) Silence(d time.Duration) { s.d = d }
+
+func TestBot_Run(t *testing.T) {
+	t.Parallel()
+
+	m := garcon.NewMaintenance("", 0)
+	s := &recordSilencer{}
+	b := garcon.NewBot(m, s)
+	b.Probes = []garcon.ProbeFunction{
+		func() []byte { return nil },
+		func() []byte { return []byte("db down") },
+	}
+
+	if reply := b.Run("/mute"); s.d != time.Hour || !strings.HasPrefix(reply, "Alerts muted for 1h") {
+		t.Errorf("/mute: d=%v reply=%q", s.d, reply)
+	}
+	if reply := b.Run("/mute 30m"); s.d != 30*time.Minute {
+		t.Errorf("/mute 30m: d=%v reply=%q", s.d, reply)
+	}
+	if reply := b.Run("/mute off"); s.d != 0 || reply != "Alerts unmuted" {
+		t.Errorf("/mute off: d=%v reply=%q", s.d, reply)
+	}
+	if reply := b.Run("/mute soon"); !strings.HasPrefix(reply, "Invalid duration") {
+		t.Errorf("/mute soon: reply=%q", reply)
+	}
+
+	if reply := b.Run("/ready@my_bot off"); !m.Draining() || reply != "Ready: off" {
+		t.Errorf("/ready off: draining=%v reply=%q", m.Draining(), reply)
+	}
+	if reply := b.Run("/ready on"); m.Draining() || reply != "Ready: on" {
+		t.Errorf("/ready on: draining=%v reply=%q", m.Draining(), reply)
+	}
+
+	reply := b.Run("/status")
+	for _, want := range []string{"Version: ", "Uptime: ", "Ready: on", "Probe #2 failed: db down", "Probes: 1/2 OK"} {
+		if !strings.Contains(reply, want) {
+			t.Errorf("/status: missing %q in %q", want, reply)
+		}
+	}
+
+	if reply := b.Run("/deploy"); !strings.HasPrefix(reply, "Unknown command /deploy") {
+		t.Errorf("/deploy: reply=%q", reply)
+	}
+}
+
+func TestBot_TelegramHandler(t *testing.T) {
+	t.Parallel()
+
+	b := garcon.NewBot(nil)
+	b.Handle("ping", "answer pong", func([]string) string { return "pong" })
+	h := b.TelegramHandler("s3cr3t", "42")
+
+	cases := []struct {
+		name   string
+		secret string
+		body   string
+		reply  string
+		status int
+	}{
+		{"authorized", "s3cr3t", `{"message":{"chat":{"id":42},"text":"/ping"}}`, "pong", http.StatusOK},
+		{"unauthorized-chat", "s3cr3t", `{"message":{"chat":{"id":666},"text":"/ping"}}`, "", http.StatusOK},
+		{"not-a-command", "s3cr3t", `{"message":{"chat":{"id":42},"text":"hello"}}`, "", http.StatusOK},
+		{"invalid-secret", "wrong", `{"message":{"chat":{"id":42},"text":"/ping"}}`, "", http.StatusUnauthorized},
+		{"missing-secret", "", `{"message":{"chat":{"id":42},"text":"/ping"}}`, "", http.StatusUnauthorized},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodPost, "/telegram", strings.NewReader(c.body))
+			r.Header.Set("X-Telegram-Bot-Api-Secret-Token", c.secret)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != c.status {
+				t.Fatalf("status=%d want %d", w.Code, c.status)
+			}
+			if c.reply == "" {
+				if c.status == http.StatusOK && w.Body.Len() > 0 {
+					t.Errorf("unexpected reply %q", w.Body.String())
+				}
+				return
+			}
+
+			var resp map[string]string
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp["method"] != "sendMessage" || resp["chat_id"] != "42" || resp["text"] != c.reply {
+				t.Errorf("reply=%v", resp)
+			}
+		})
+	}
+}
+
+func TestBot_MattermostHandler(t *testing.T) {
+	t.Parallel()
+
+	m := garcon.NewMaintenance("", 0)
+	h := garcon.NewBot(m).MattermostHandler("tok")
+
+	cases := []struct {
+		name   string
+		form   url.Values
+		status int
+		drain  bool
+	}{
+		{"single-command", url.Values{"token": {"tok"}, "command": {"/ops"}, "text": {"ready off"}}, http.StatusOK, true},
+		{"command-per-slash", url.Values{"token": {"tok"}, "command": {"/ready"}, "text": {"on"}}, http.StatusOK, false},
+		{"invalid-token", url.Values{"token": {"bad"}, "command": {"/ready"}, "text": {"off"}}, http.StatusUnauthorized, false},
+	}
+
+	for _, c := range cases {
+		r := httptest.NewRequest(http.MethodPost, "/mattermost", strings.NewReader(c.form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if w.Code != c.status {
+			t.Errorf("%s: status=%d want %d", c.name, w.Code, c.status)
+		}
+		if m.Draining() != c.drain {
+			t.Errorf("%s: draining=%v want %v", c.name, m.Draining(), c.drain)
+		}
+		if c.status == http.StatusOK && !strings.Contains(w.Body.String(), `"response_type":"ephemeral"`) {
+			t.Errorf("%s: body=%s", c.name, w.Body.String())
+		}
+	}
+}
+
+func TestMutedNotifier_Silence(t *testing.T) {
+	t.Parallel()
+
+	rec := &recordNotifier{}
+	mn := garcon.NewMutedNotifier(rec, 3, time.Hour)
+	mn.CheckInterval = time.Hour
+
+	mn.Silence(time.Hour)
+	for range 5 {
+		mn.Notify("disk full")
+	}
+	if len(rec.messages) != 0 {
+		t.Fatalf("got %d messages during the silence", len(rec.messages))
+	}
+
+	mn.Silence(0)
+	if err := mn.Check(); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.messages) != 1 || !strings.HasPrefix(rec.messages[0], "Silence ended: 5 messages muted") {
+		t.Errorf("digest=%q", rec.messages)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ETag returns the strong entity tag of the body: the quoted first 128 bits of its SHA-256.
+// The digest is deterministic: the ETag of a same body does not change
+// when the server restarts, and is the same for all the replicas.
+func ETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// LastModified sets the "Last-Modified" header with the timestamp of the resource
+// and answers "304 Not Modified" when the "If-Modified-Since" header is not older.
+// The handler returns immediately when LastModified returns true:
+//
+//	if garcon.LastModified(w, r, prices.UpdatedAt) {
+//		return // 304 Not Modified
+//	}
+//	garcon.WriteOKFor(w, r, prices)
+//
+// When the request has an "If-None-Match" header, LastModified returns false:
+// WriteOKFor compares the ETag instead (RFC 9110 §13.2.2).
+func LastModified(w http.ResponseWriter, r *http.Request, t time.Time) bool {
+	if t.IsZero() {
+		return false
+	}
+	w.Header().Set("Last-Modified", t.UTC().Format(http.TimeFormat))
+	return notModifiedSince(w, r)
+}
+
+// notModified sets the ETag of the body and answers "304 Not Modified"
+// when the conditional GET matches: If-None-Match, else If-Modified-Since.
+func notModified(w http.ResponseWriter, r *http.Request, body []byte) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	etag := ETag(body)
+	w.Header().Set("ETag", etag)
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		if !etagMatch(inm, etag) {
+			return false
+		}
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+
+	return notModifiedSince(w, r)
+}
+
+// notModifiedSince compares the "Last-Modified" header (set by the handler)
+// with the "If-Modified-Since" header of the request.
+func notModifiedSince(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	if r.Header.Get("If-None-Match") != "" {
+		return false
+	}
+
+	ims, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	lm, err := http.ParseTime(w.Header().Get("Last-Modified"))
+	if err != nil || lm.After(ims) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagMatch implements the weak comparison of If-None-Match:
+// the "W/" prefix is ignored and "*" matches any ETag.
+func etagMatch(ifNoneMatch, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/teal-finance/garcon"
+)
+
+func TestWriteOK_ETag(t *testing.T) {
+	t.Parallel()
+
+	w := httptest.NewRecorder()
+	garcon.WriteOKFor(w, httptest.NewRequest(http.MethodGet, "/", nil), "k", "v")
+	etag := w.Header().Get("ETag")
+	if etag != garcon.ETag(w.Body.Bytes()) {
+		t.Fatalf("ETag=%q want the hash of %s", etag, w.Body.String())
+	}
+
+	cases := []struct {
+		name        string
+		method      string
+		ifNoneMatch string
+		status      int
+	}{
+		{"same", http.MethodGet, etag, http.StatusNotModified},
+		{"weak", http.MethodGet, "W/" + etag, http.StatusNotModified},
+		{"list", http.MethodGet, `"xyz", ` + etag, http.StatusNotModified},
+		{"star", http.MethodHead, "*", http.StatusNotModified},
+		{"other", http.MethodGet, `"xyz"`, http.StatusOK},
+		{"post", http.MethodPost, etag, http.StatusOK},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(c.method, "/", nil)
+			r.Header.Set("If-None-Match", c.ifNoneMatch)
+			w := httptest.NewRecorder()
+			garcon.WriteOKFor(w, r, "k", "v")
+
+			if w.Code != c.status {
+				t.Errorf("status=%d want %d", w.Code, c.status)
+			}
+			if c.status == http.StatusNotModified && w.Body.Len() > 0 {
+				t.Errorf("304 with body %q", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestETag_Deterministic(t *testing.T) {
+	t.Parallel()
+
+	// the same ETag after a restart and on all the replicas
+	if got := garcon.ETag(nil); got != `"e3b0c44298fc1c149afbf4c8996fb924"` {
+		t.Errorf("ETag(empty)=%s", got)
+	}
+}
+
+func TestLastModified(t *testing.T) {
+	t.Parallel()
+
+	modTime := time.Date(2026, 10, 1, 12, 0, 0, 500, time.UTC)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if garcon.LastModified(w, r, modTime) {
+			return
+		}
+		garcon.WriteOKFor(w, r, "k", "v")
+	}
+
+	cases := []struct {
+		name        string
+		since       time.Time
+		ifNoneMatch string
+		status      int
+	}{
+		{"same", modTime, "", http.StatusNotModified},
+		{"later", modTime.Add(time.Hour), "", http.StatusNotModified},
+		{"older", modTime.Add(-time.Hour), "", http.StatusOK},
+		{"If-None-Match has precedence", modTime, `"xyz"`, http.StatusOK},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("If-Modified-Since", c.since.Format(http.TimeFormat))
+			if c.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", c.ifNoneMatch)
+			}
+			w := httptest.NewRecorder()
+			handler(w, r)
+
+			if w.Code != c.status {
+				t.Errorf("status=%d want %d", w.Code, c.status)
+			}
+			if got := w.Header().Get("Last-Modified"); got != modTime.Format(http.TimeFormat) {
+				t.Errorf("Last-Modified=%q", got)
+			}
+		})
+	}
+}
@@ -130,7 +130,7 @@ func oneOrigin(allowedOrigin string) func(string) bool {
 			return true
 		}
 
-		log.Security("CORS Refuse", origin, "is not "+allowedOrigin)
+		dedup.log(log.Security, "CORS Refuse origin not allowed: "+allowedOrigin, "origin="+gg.Sanitize(origin))
 		return false
 	}
 }
@@ -145,7 +145,7 @@ func multipleOriginPrefixes(allowedPrefixes []string) func(origin string) bool {
 			}
 		}
 
-		log.Security("CORS Refuse", origin, "without prefixes", allowedPrefixes)
+		dedup.log(log.Security, "CORS Refuse origin without prefixes: "+strings.Join(allowedPrefixes, " "), "origin="+gg.Sanitize(origin))
 		return false
 	}
 }
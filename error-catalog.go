@@ -0,0 +1,181 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// APIError is an error registered in the error catalogue (see RegisterError).
+// The clients identify the error with its stable Code rather than the message.
+// Writer.WriteErr recognizes the APIError (even wrapped, see errors.As)
+// passed as the first key-value: the response contains the "code"
+// and the doc URL is completed with the Anchor.
+type APIError struct {
+	Code    string // stable identifier, e.g. "path_traversal"
+	Message string // default message
+	Anchor  string // appended to the doc URL of the Writer, default is "#" + Code
+	Status  int    // HTTP status code
+}
+
+func (e *APIError) Error() string { return e.Message }
+
+//nolint:gochecknoglobals // the catalogue is filled at init time by RegisterError
+var catalog = struct {
+	errors map[string]*APIError
+	mu     sync.Mutex
+}{errors: make(map[string]*APIError), mu: sync.Mutex{}}
+
+// RegisterError adds an error to the catalogue.
+// The code must be unique and contain only lower-case letters, digits and "_".
+// RegisterError panics on invalid or duplicated code:
+// call it at init time, like errors.New.
+//
+//	var ErrOrderNotFound = garcon.RegisterError(http.StatusNotFound, "order_not_found", "order not found")
+func RegisterError(status int, code, message string) *APIError {
+	if code == "" || strings.Trim(code, "abcdefghijklmnopqrstuvwxyz0123456789_") != "" {
+		log.Panicf("garcon.RegisterError(%q) code must match [a-z0-9_]+", code)
+	}
+	if http.StatusText(status) == "" {
+		log.Panicf("garcon.RegisterError(%q) invalid HTTP status %d", code, status)
+	}
+
+	e := &APIError{
+		Code:    code,
+		Message: message,
+		Anchor:  "#" + code,
+		Status:  status,
+	}
+
+	catalog.mu.Lock()
+	defer catalog.mu.Unlock()
+
+	if _, dup := catalog.errors[code]; dup {
+		log.Panicf("garcon.RegisterError(%q) code already registered", code)
+	}
+	catalog.errors[code] = e
+
+	return e
+}
+
+// Errors registered by Garcon (see also the JWT errors).
+var (
+	ErrPathTraversal   = RegisterError(http.StatusBadRequest, "path_traversal", "URL contains '..'")
+	ErrPathInvalid     = RegisterError(http.StatusBadRequest, "path_invalid", pathInvalid)
+	ErrPathReserved    = RegisterError(http.StatusNotImplemented, "path_reserved", pathReserved)
+	ErrUnprintableURI  = RegisterError(http.StatusBadRequest, "unprintable_uri", "Invalid URI with non-printable symbol")
+	ErrTooManyRequests = RegisterError(http.StatusTooManyRequests, "too_many_requests", "Too Many Requests")
+	ErrMaintenance     = RegisterError(http.StatusServiceUnavailable, "maintenance", maintenanceMsg)
+	ErrInternal        = RegisterError(http.StatusInternalServerError, "internal_error", "Internal Server Error")
+	ErrInvalidWebForm  = RegisterError(http.StatusBadRequest, "invalid_webform", "cannot parse the webform")
+)
+
+// ErrorCatalog returns the registered errors sorted by code.
+func ErrorCatalog() []*APIError {
+	catalog.mu.Lock()
+	list := make([]*APIError, 0, len(catalog.errors))
+	for _, e := range catalog.errors {
+		list = append(list, e)
+	}
+	catalog.mu.Unlock()
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Code < list[j].Code })
+	return list
+}
+
+// ErrorCatalogJSON exports the error catalogue in JSON for the API documentation.
+// The docURL (can be empty) is completed with the anchor of each error.
+func ErrorCatalogJSON(docURL string) ([]byte, error) {
+	type entry struct {
+		Code    string `json:"code"`
+		Title   string `json:"title"`
+		Message string `json:"message"`
+		Doc     string `json:"doc,omitempty"`
+		Status  int    `json:"status"`
+	}
+
+	list := ErrorCatalog()
+	entries := make([]entry, 0, len(list))
+	for _, e := range list {
+		doc := ""
+		if docURL != "" {
+			doc = docURL + e.Anchor
+		}
+		entries = append(entries, entry{
+			Code:    e.Code,
+			Title:   http.StatusText(e.Status),
+			Message: e.Message,
+			Doc:     doc,
+			Status:  e.Status,
+		})
+	}
+
+	return json.MarshalIndent(entries, "", "  ")
+}
+
+// ErrorCatalogMarkdown exports the error catalogue in Markdown for the API documentation.
+// Each error has its own heading: the default anchors ("#" + code) target these headings.
+func ErrorCatalogMarkdown() string {
+	var sb strings.Builder
+	sb.WriteString("# Errors\n\n")
+	sb.WriteString("| Code | HTTP status | Message |\n")
+	sb.WriteString("|------|-------------|---------|\n")
+
+	list := ErrorCatalog()
+	for _, e := range list {
+		fmt.Fprintf(&sb, "| [`%s`](%s) | %d %s | %s |\n",
+			e.Code, e.Anchor, e.Status, http.StatusText(e.Status), strings.ReplaceAll(e.Message, "|", `\|`))
+	}
+
+	for _, e := range list {
+		sb.WriteString("\n## " + e.Code + "\n\n")
+		sb.WriteString("HTTP status: " + strconv.Itoa(e.Status) + " " + http.StatusText(e.Status) + "\n\n")
+		sb.WriteString(e.Message + "\n")
+	}
+
+	return sb.String()
+}
+
+// apiError detects an APIError as the first key-value:
+// apiError inserts its code in the key-values,
+// completes the doc URL with its anchor
+// and uses its HTTP status when statusCode is zero.
+func (gw Writer) apiError(statusCode int, kv []any) (Writer, int, []any) {
+	if len(kv) == 0 {
+		return gw, statusCode, kv
+	}
+	err, ok := kv[0].(error)
+	if !ok {
+		return gw, statusCode, kv
+	}
+	var e *APIError
+	if !errors.As(err, &e) {
+		return gw, statusCode, kv
+	}
+
+	if statusCode == 0 {
+		statusCode = e.Status
+	}
+	if gw != "" {
+		gw += Writer(e.Anchor)
+	}
+
+	msg := kv[0]
+	rest := kv[1:]
+	if len(kv) == 2 { // same concatenation as appendMessages
+		msg = fmt.Sprintf("%v%v", kv[0], kv[1])
+		rest = nil
+	}
+
+	return gw, statusCode, append([]any{msg, "code", e.Code}, rest...)
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/teal-finance/garcon"
+)
+
+var errOrderNotFound = garcon.RegisterError(http.StatusNotFound, "order_not_found", "order not found")
+
+func TestWriter_WriteErr_APIError(t *testing.T) {
+	t.Parallel()
+
+	gw := garcon.NewWriter("https://example.com/doc")
+	wrapped := fmt.Errorf("order #42: %w", errOrderNotFound)
+
+	cases := []struct {
+		name   string
+		accept string
+		kv     []any
+		want   map[string]any
+		status int
+	}{
+		{
+			"legacy", "", []any{wrapped, "order_id", 42},
+			map[string]any{"message": "order #42: order not found", "code": "order_not_found", "order_id": 42.0, "doc": "https://example.com/doc#order_not_found"},
+			http.StatusNotFound,
+		},
+		{
+			"legacy-concatenation", "", []any{errOrderNotFound, " (archived)"},
+			map[string]any{"message": "order not found (archived)", "code": "order_not_found", "doc": "https://example.com/doc#order_not_found"},
+			http.StatusNotFound,
+		},
+		{
+			"problem", "application/problem+json", []any{wrapped},
+			map[string]any{"detail": "order #42: order not found", "code": "order_not_found", "type": "https://example.com/doc#order_not_found", "status": 404.0},
+			http.StatusNotFound,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/orders/42", nil)
+			r.Header.Set("Accept", c.accept)
+			w := httptest.NewRecorder()
+			gw.WriteErr(w, r, 0, c.kv...) // zero => status from the catalogue
+
+			if w.Code != c.status {
+				t.Errorf("status=%d want %d", w.Code, c.status)
+			}
+
+			var body map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid JSON %s: %v", w.Body.String(), err)
+			}
+			for k, v := range c.want {
+				if body[k] != v {
+					t.Errorf("%s=%v want %v", k, body[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestErrorCatalog(t *testing.T) {
+	t.Parallel()
+
+	buf, err := garcon.ErrorCatalogJSON("https://example.com/doc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []map[string]any
+	if err = json.Unmarshal(buf, &entries); err != nil {
+		t.Fatal(err)
+	}
+
+	found := false
+	for _, e := range entries {
+		if e["code"] == "no_valid_jwt" {
+			found = true
+			if e["status"] != 401.0 || e["doc"] != "https://example.com/doc#no_valid_jwt" {
+				t.Errorf("entry=%v", e)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("no_valid_jwt missing in %s", buf)
+	}
+
+	md := garcon.ErrorCatalogMarkdown()
+	for _, want := range []string{
+		"| [`path_traversal`](#path_traversal) | 400 Bad Request | URL contains '..' |",
+		"\n## too_many_requests\n\nHTTP status: 429 Too Many Requests\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("missing %q in Markdown", want)
+		}
+	}
+}
+
+func TestRegisterError_Panics(t *testing.T) {
+	t.Parallel()
+
+	for _, code := range []string{"path_traversal", "Bad-Code", ""} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterError(%q) did not panic", code)
+				}
+			}()
+			garcon.RegisterError(http.StatusBadRequest, code, "message")
+		}()
+	}
+}
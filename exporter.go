@@ -106,6 +106,16 @@ func (ns ServerName) newGauge(name, help string) prometheus.Gauge {
 	})
 }
 
+func (ns ServerName) newCounterVec(name, help string, labels ...string) *prometheus.CounterVec {
+	return promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   string(ns),
+		Subsystem:   "http",
+		Name:        name,
+		Help:        help,
+		ConstLabels: nil,
+	}, labels)
+}
+
 func (ns ServerName) newCounter(name, help string) prometheus.Counter {
 	return promauto.NewCounter(prometheus.CounterOpts{
 		Namespace:   string(ns),
@@ -116,66 +126,69 @@ func (ns ServerName) newCounter(name, help string) prometheus.Counter {
 	})
 }
 
-type statusRecorder struct {
-	http.ResponseWriter
-	StatusCode int
-}
-
-func (r *statusRecorder) WriteHeader(status int) {
-	r.ResponseWriter.WriteHeader(status)
-	r.StatusCode = status
-}
-
-// MiddlewareExportTrafficMetrics measures the duration to process a request.
+// MiddlewareExportTrafficMetrics measures the duration to process a request,
+// the time-to-first-byte and the response size.
 func (ns ServerName) MiddlewareExportTrafficMetrics(next http.Handler) http.Handler {
 	summary := ns.newSummaryVec(
 		"request_duration_seconds",
 		"Time to handle a client request",
 		"code",
 		"route")
+	ttfb := ns.newSummaryVec(
+		"time_to_first_byte_seconds",
+		"Time until the response header is sent",
+		"code",
+		"route")
+	size := ns.newCounterVec(
+		"response_bytes_total",
+		"Total bytes written in the response bodies",
+		"code")
+	notModified := ns.newCounterVec(
+		"not_modified_total",
+		"Conditional requests answered by 304 Not Modified (body not sent)",
+		"route")
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		record := &statusRecorder{ResponseWriter: w, StatusCode: http.StatusOK}
-
-		start := time.Now()
+		record := newResponseRecorder(w)
 		next.ServeHTTP(record, r)
-		duration := time.Since(start)
+		d := record.Duration()
 
 		code := StatusCodeStr(record.StatusCode)
-		summary.WithLabelValues(code, r.RequestURI).Observe(duration.Seconds())
-		log.Out(ipMethodURLDurationSafe(r, code, duration))
+		summary.WithLabelValues(code, r.RequestURI).Observe(d.Seconds())
+		ttfb.WithLabelValues(code, r.RequestURI).Observe(record.TTFB().Seconds())
+		size.WithLabelValues(code).Add(float64(record.Bytes))
+		if record.StatusCode == http.StatusNotModified {
+			notModified.WithLabelValues(route(r)).Inc()
+		}
+		log.Out(ipMethodURLDurationSafe(r, code, d, record.Bytes))
 	})
 }
 
-// MiddlewareLogDuration logs the requested URL along with the time to handle it.
+// MiddlewareLogDuration logs the requested URL along with the time to handle it and the response size.
 func MiddlewareLogDuration(next http.Handler) http.Handler {
-	log.Info("MiddlewareLogDuration logs requester IP, request URL and duration")
+	log.Info("MiddlewareLogDuration logs requester IP, request URL, duration and response size")
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		record := &statusRecorder{ResponseWriter: w, StatusCode: http.StatusOK}
-
-		start := time.Now()
+		record := newResponseRecorder(w)
 		next.ServeHTTP(record, r)
-		d := time.Since(start)
+		d := record.Duration()
 
 		code := StatusCodeStr(record.StatusCode)
-		log.Out(ipMethodURLDuration(r, code, d))
+		log.Out(ipMethodURLDuration(r, code, d, record.Bytes))
 	})
 }
 
 // MiddlewareLogDurationSafe is similar to MiddlewareLogDurations but also sanitizes the URL.
 func MiddlewareLogDurationSafe(next http.Handler) http.Handler {
-	log.Info("MiddlewareLogDurationSafe: logs requester IP, sanitized URL and duration")
+	log.Info("MiddlewareLogDurationSafe: logs requester IP, sanitized URL, duration and response size")
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		record := &statusRecorder{ResponseWriter: w, StatusCode: http.StatusOK}
-
-		start := time.Now()
-		next.ServeHTTP(w, r)
-		d := time.Since(start)
+		record := newResponseRecorder(w)
+		next.ServeHTTP(record, r)
+		d := record.Duration()
 
 		code := StatusCodeStr(record.StatusCode)
-		log.Out(ipMethodURLDurationSafe(r, code, d))
+		log.Out(ipMethodURLDurationSafe(r, code, d, record.Bytes))
 	})
 }
 
@@ -197,26 +210,41 @@ func (g *Garcon) MiddlewareLogRequest(settings ...string) gg.Middleware {
 		}
 	}
 
+	if g.logSampler != nil {
+		line := ipMethodURL
+		if logSafe {
+			line = ipMethodURLSafe
+		}
+		if logFingerprint {
+			ipMethodURL := line
+			line = func(r *http.Request) string { return ipMethodURL(r) + fingerprint(r) }
+		}
+		return g.withPrivacy(g.logSampler.middlewareLogRequest(line))
+	}
+
 	if logFingerprint {
 		if logSafe {
-			return MiddlewareLogFingerprintSafe
+			return g.withPrivacy(MiddlewareLogFingerprintSafe)
 		}
-		return MiddlewareLogFingerprint
+		return g.withPrivacy(MiddlewareLogFingerprint)
 	}
 
 	if logSafe {
-		return MiddlewareLogRequestSafe
+		return g.withPrivacy(MiddlewareLogRequestSafe)
 	}
-	return MiddlewareLogRequest
+	return g.withPrivacy(MiddlewareLogRequest)
 }
 
 // MiddlewareLogDuration logs the requested URL along with its handling time.
 // When the optional parameter safe is true, this middleware sanitizes the URL before printing it.
 func (g *Garcon) MiddlewareLogDuration(safe ...bool) gg.Middleware {
+	if g.logSampler != nil {
+		return g.withPrivacy(g.logSampler.middlewareLogDuration(len(safe) > 0 && safe[0]))
+	}
 	if len(safe) > 0 && safe[0] {
-		return MiddlewareLogDurationSafe
+		return g.withPrivacy(MiddlewareLogDurationSafe)
 	}
-	return MiddlewareLogDuration
+	return g.withPrivacy(MiddlewareLogDuration)
 }
 
 // MiddlewareLogRequest is the middleware to log the requester IP and the requested URL.
@@ -338,6 +366,8 @@ func newExporterHandler(options ...ProbeOption) http.Handler {
 	h := &exporterHandler{
 		livenessProbes:  []ProbeFunction{},
 		readinessProbes: []ProbeFunction{},
+		adminToken:      nil,
+		maintenance:     nil,
 	}
 
 	for _, option := range options {
@@ -350,6 +380,8 @@ func newExporterHandler(options ...ProbeOption) http.Handler {
 type exporterHandler struct {
 	livenessProbes  []ProbeFunction
 	readinessProbes []ProbeFunction
+	adminToken      []byte
+	maintenance     *Maintenance
 }
 
 // ServeHTTP implements http.Handler interface.
@@ -360,14 +392,35 @@ func (h *exporterHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	case "/health":
 		handleEndpoint(w, h.livenessProbes)
 	case "/ready":
+		if h.maintenance != nil && h.maintenance.Draining() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(drainingMsg))
+			return
+		}
 		handleEndpoint(w, append(h.livenessProbes, h.readinessProbes...))
+	case "/maintenance":
+		if h.maintenance == nil {
+			h.notFound(w, r)
+			return
+		}
+		h.handleAdmin(w, r, "maintenance", h.maintenance.Enabled, h.maintenance.Enable)
+	case "/drain":
+		if h.maintenance == nil {
+			h.notFound(w, r)
+			return
+		}
+		h.handleAdmin(w, r, "drain", h.maintenance.Draining, h.maintenance.Drain)
 	default:
-		log.Warning(ipMethodURLSafe(r) + " on Exporter Server")
-		w.WriteHeader(http.StatusNotFound)
-		w.Write([]byte(`{"message":"This is the Exporter/Health Server"}`))
+		h.notFound(w, r)
 	}
 }
 
+func (*exporterHandler) notFound(w http.ResponseWriter, r *http.Request) {
+	log.Warning(ipMethodURLSafe(r) + " on Exporter Server")
+	w.WriteHeader(http.StatusNotFound)
+	w.Write([]byte(`{"message":"This is the Exporter/Health Server"}`))
+}
+
 func handleEndpoint(w http.ResponseWriter, probes []ProbeFunction) {
 	for _, p := range probes {
 		txt := p()
@@ -381,23 +434,35 @@ func handleEndpoint(w http.ResponseWriter, probes []ProbeFunction) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// ctxSuffix appends the request ID and the trace ID (if any) to the log lines.
+func ctxSuffix(r *http.Request) string {
+	suffix := ""
+	if id := gg.RequestIDFromCtx(r.Context()); id != "" {
+		suffix = " req=" + id
+	}
+	if id := TraceIDFromCtx(r.Context()); id != "" {
+		suffix += " trace=" + id
+	}
+	return suffix
+}
+
 func ipMethodURL(r *http.Request) string {
 	// double space after "in" is for padding with "out" logs
-	return "--> " + r.RemoteAddr + " " + r.Method + " " + r.RequestURI
+	return "--> " + remoteAddr(r) + " " + r.Method + " " + r.RequestURI + ctxSuffix(r)
 }
 
 func ipMethodURLSafe(r *http.Request) string {
-	return "--> " + r.RemoteAddr + " " + r.Method + " " + gg.Sanitize(r.RequestURI)
+	return "--> " + remoteAddr(r) + " " + r.Method + " " + gg.Sanitize(r.RequestURI) + ctxSuffix(r)
 }
 
-func ipMethodURLDuration(r *http.Request, statusCode string, d time.Duration) string {
-	return statusCode + " " + r.RemoteAddr + " " + r.Method + " " +
-		r.RequestURI + " " + d.String()
+func ipMethodURLDuration(r *http.Request, statusCode string, d time.Duration, size int64) string {
+	return statusCode + " " + remoteAddr(r) + " " + r.Method + " " +
+		r.RequestURI + " " + d.String() + " " + gg.ConvertSize64(size) + ctxSuffix(r)
 }
 
-func ipMethodURLDurationSafe(r *http.Request, statusCode string, d time.Duration) string {
-	return statusCode + " " + r.RemoteAddr + " " + r.Method + " " +
-		gg.Sanitize(r.RequestURI) + " " + d.String()
+func ipMethodURLDurationSafe(r *http.Request, statusCode string, d time.Duration, size int64) string {
+	return statusCode + " " + remoteAddr(r) + " " + r.Method + " " +
+		gg.Sanitize(r.RequestURI) + " " + d.String() + " " + gg.ConvertSize64(size) + ctxSuffix(r)
 }
 
 // FingerprintExplanation provides a description of the logged HTTP headers.
@@ -425,7 +490,7 @@ func fingerprint(r *http.Request) string {
 		// 1. Accept-Language, the language preferred by the user.
 		gg.SafeHeader(r, "Accept-Language") + " " +
 		// 2. User-Agent, name and version of the browser and OS.
-		gg.SafeHeader(r, "User-Agent") +
+		safeHeader(r, "User-Agent") +
 		// 3. R=Referer, the website from which the request originated.
 		headerTxt(r, "Referer", "R=", "") +
 		// 4. A=Accept, the content types the browser prefers.
@@ -455,36 +520,45 @@ func fingerprint(r *http.Request) string {
 // FingerprintMD provide the browser fingerprint in markdown format.
 // Attention: read the .
 func FingerprintMD(r *http.Request) string {
-	return "\n" + "- **IP**: " + gg.Sanitize(r.RemoteAddr) +
-		headerMD(r, "Accept-Language") + // language preferred by the user
-		headerMD(r, "User-Agent") + // name and version of browser and OS
-		headerMD(r, "Referer") + // URL from which the request originated
-		headerMD(r, "Accept") + // content types the browser prefers
-		headerMD(r, "Accept-Encoding") + // compression formats the browser supports
-		headerMD(r, "Connection") + // can be: empty, "keep-alive" or "close"
-		headerMD(r, "Cache-Control") + // how the browser is caching data
-		headerMD(r, "DNT") + // "Do Not Track" is being dropped by web standards and browsers
-		headerMD(r, "Via") + // avoid request loops and identify protocol capabilities
-		headerMD(r, "Authorization") + // Attention: may contain confidential data
-		headerMD(r, "Cookie") // Attention: may contain confidential data
+	md := ""
+	for _, f := range FingerprintFields(r) {
+		md += "\n" + "- **" + f.Name + "**: " + f.Value
+	}
+	return md
+}
+
+// FingerprintFields is FingerprintMD as the fields of a gg.Message
+// (escaped by the notifiers when rendered).
+func FingerprintFields(r *http.Request) []gg.Field {
+	fields := []gg.Field{{Name: "IP", Value: gg.Sanitize(remoteAddr(r))}}
+	for _, header := range []string{
+		"Accept-Language", // language preferred by the user
+		"User-Agent",      // name and version of browser and OS
+		"Referer",         // URL from which the request originated
+		"Accept",          // content types the browser prefers
+		"Accept-Encoding", // compression formats the browser supports
+		"Connection",      // can be: empty, "keep-alive" or "close"
+		"Cache-Control",   // how the browser is caching data
+		"DNT",             // "Do Not Track" is being dropped by web standards and browsers
+		"Via",             // avoid request loops and identify protocol capabilities
+		"Authorization",   // Attention: may contain confidential data
+		"Cookie",          // Attention: may contain confidential data
+	} {
+		if v := safeHeader(r, header); v != "" {
+			fields = append(fields, gg.Field{Name: header, Value: v})
+		}
+	}
+	return fields
 }
 
 func headerTxt(r *http.Request, header, key, skip string) string {
-	v := gg.SafeHeader(r, header)
+	v := safeHeader(r, header)
 	if v == skip {
 		return ""
 	}
 	return " " + key + v
 }
 
-func headerMD(r *http.Request, header string) string {
-	v := gg.SafeHeader(r, header)
-	if v == "" {
-		return ""
-	}
-	return "\n" + "- **" + header + "**: " + v
-}
-
 func StatusCodeStr(code int) string {
 	// fast path for common codes
 	switch code {
@@ -27,6 +27,9 @@ var log = emo.NewZone("garcon")
 type Garcon struct {
 	ServerName     ServerName
 	Writer         Writer
+	privacy        *gg.Pseudonymizer
+	locales        *Locales
+	logSampler     *LogSampler
 	docURL         string
 	urls           []*url.URL
 	allowedOrigins []string
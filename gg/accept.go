@@ -0,0 +1,101 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package gg
+
+import (
+	"strconv"
+	"strings"
+)
+
+// AcceptItem is a media range (or language range) with its quality value.
+type AcceptItem struct {
+	Value string  // e.g. "application/json", "text/*", "fr-CH"
+	Q     float64 // quality between 0 and 1
+}
+
+// ParseAccept parses the HTTP header "Accept" (or "Accept-Language"...)
+// such as "text/html, application/json;q=0.9, */*;q=0.1".
+// The values are lower-cased, the parameters other than "q" are dropped.
+// The order of the header is preserved.
+func ParseAccept(header string) []AcceptItem {
+	var items []AcceptItem
+	for _, part := range strings.Split(header, ",") {
+		value, params, _ := strings.Cut(part, ";")
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, p := range strings.Split(params, ";") {
+			k, v, _ := strings.Cut(p, "=")
+			if strings.TrimSpace(k) == "q" {
+				f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+				if err == nil && f >= 0 && f <= 1 {
+					q = f
+				}
+			}
+		}
+
+		items = append(items, AcceptItem{Value: value, Q: q})
+	}
+	return items
+}
+
+// Negotiate returns the offered media type preferred by the HTTP header "Accept".
+// The quality of an offer is given by the most specific matching media range:
+// "type/subtype" > "type/*" > "*/*".
+// When several offers have the same quality, the first offer wins.
+// Negotiate returns the first offer when the header is empty,
+// and returns an empty string when no offer is acceptable.
+func Negotiate(accept string, offers ...string) string {
+	if len(offers) == 0 {
+		return ""
+	}
+	if strings.TrimSpace(accept) == "" {
+		return offers[0]
+	}
+
+	items := ParseAccept(accept)
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		if q := mediaQuality(items, strings.ToLower(offer)); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
+
+// mediaQuality returns the quality of the most specific media range matching the offer.
+func mediaQuality(items []AcceptItem, offer string) float64 {
+	typ, _, _ := strings.Cut(offer, "/")
+
+	q, specificity := 0.0, -1
+	for _, it := range items {
+		s := -1
+		switch it.Value {
+		case offer:
+			s = 2
+		case typ + "/*":
+			s = 1
+		case "*/*", "*":
+			s = 0
+		}
+		if s > specificity {
+			q, specificity = it.Q, s
+		}
+	}
+	return q
+}
+
+// Quality returns the quality of the offered media type in the HTTP header "Accept":
+// 1 when the header is empty, 0 when the offer is not acceptable.
+func Quality(accept, offer string) float64 {
+	if strings.TrimSpace(accept) == "" {
+		return 1
+	}
+	return mediaQuality(ParseAccept(accept), strings.ToLower(offer))
+}
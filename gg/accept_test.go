@@ -0,0 +1,78 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package gg_test
+
+import (
+	"testing"
+
+	"github.com/teal-finance/garcon/gg"
+)
+
+func TestParseAccept(t *testing.T) {
+	t.Parallel()
+
+	got := gg.ParseAccept("text/html, Application/JSON;charset=utf-8;q=0.9, */*;q=0.1, bad;q=2, ")
+	want := []gg.AcceptItem{{"text/html", 1}, {"application/json", 0.9}, {"*/*", 0.1}, {"bad", 1}}
+
+	if len(got) != len(want) {
+		t.Fatalf("ParseAccept() = %v want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("#%d: %v want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	t.Parallel()
+
+	offers := []string{"application/json", "application/problem+json"}
+
+	cases := []struct {
+		accept string
+		want   string
+	}{
+		{"", "application/json"},
+		{"*/*", "application/json"},
+		{"application/problem+json", "application/problem+json"},
+		{"application/json, application/problem+json", "application/json"},
+		{"application/json;q=0.5, application/problem+json", "application/problem+json"},
+		{"application/*;q=0.5, application/problem+json;q=0.4", "application/json"},
+		{"application/*, application/json;q=0", "application/problem+json"},
+		{"text/html", ""},
+	}
+
+	for _, c := range cases {
+		if got := gg.Negotiate(c.accept, offers...); got != c.want {
+			t.Errorf("Negotiate(%q) = %q want %q", c.accept, got, c.want)
+		}
+	}
+}
+
+func TestQuality(t *testing.T) {
+	t.Parallel()
+
+	accept := "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+
+	cases := []struct {
+		accept string
+		offer  string
+		want   float64
+	}{
+		{"", "application/json", 1},
+		{accept, "application/json", 0.8},
+		{accept, "application/xml", 0.9},
+		{"application/json;q=0", "application/json", 0},
+		{"text/html", "application/json", 0},
+	}
+
+	for _, c := range cases {
+		if got := gg.Quality(c.accept, c.offer); got != c.want {
+			t.Errorf("Quality(%q, %q) = %v want %v", c.accept, c.offer, got, c.want)
+		}
+	}
+}
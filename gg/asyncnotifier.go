@@ -0,0 +1,437 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package gg
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrQueueFull is returned by AsyncNotifier.Notify when the queue is full
+// and the message cannot be spooled to disk.
+var ErrQueueFull = errors.New("AsyncNotifier: queue is full, message dropped")
+
+// Default settings of the AsyncNotifier.
+const (
+	defaultAsyncMaxRetries     = 5
+	defaultAsyncInitialBackoff = time.Second
+	defaultAsyncMaxBackoff     = 5 * time.Minute
+	defaultAsyncMaxReplays     = 10
+	spoolExt                   = ".msg"    // plain text message
+	spoolMessageExt            = ".json"   // structured Message
+	deadLetterExt              = ".failed" // appended to the spooled files given up
+)
+
+// AsyncNotifier wraps a Notifier to send the messages in background:
+// Notify only enqueues the message within a bounded queue.
+// The failed messages are retried with an exponential backoff.
+// The undelivered messages are spooled to disk (one file per message)
+// and are sent again later, even after a restart.
+// The spooled messages rejected by the backend (see ErrRejected),
+// or still undelivered after MaxReplays, are renamed "*.failed" (dead letters)
+// and counted as dropped.
+// The structured messages (see NotifyMessage) are forwarded as is:
+// the wrapped notifier renders them with its own markup.
+type AsyncNotifier struct {
+	notifier Notifier
+	queue    chan asyncItem
+	done     chan struct{}
+	spoolDir string // empty = no persistence
+
+	// MaxRetries is the number of retries before spooling the message
+	// (MaxRetries=3 means up to 4 delivery attempts).
+	MaxRetries int
+
+	// InitialBackoff is the delay before the first retry, then doubled at each retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff limits the delay between retries,
+	// and is also the period to retry the spooled messages.
+	MaxBackoff time.Duration
+
+	// MaxReplays is the number of failed replays of a spooled message
+	// before giving up (the file is renamed "*.failed").
+	MaxReplays int
+
+	sent    atomic.Uint64
+	failed  atomic.Uint64
+	retries atomic.Uint64
+	dropped atomic.Uint64
+	spooled atomic.Int64
+
+	wg        sync.WaitGroup
+	startOnce sync.Once
+	closeOnce sync.Once
+}
+
+// asyncItem is a plain text message, or a structured Message when msg is not nil.
+type asyncItem struct {
+	msg  *Message
+	text string
+}
+
+func (it asyncItem) send(n Notifier) error {
+	if it.msg != nil {
+		return NotifyMessage(n, *it.msg)
+	}
+	return n.Notify(it.text)
+}
+
+// AsyncNotifierStats is a snapshot of the AsyncNotifier counters.
+type AsyncNotifierStats struct {
+	Depth   int    // messages within the queue
+	Spooled int64  // messages within the spool directory
+	Sent    uint64 // delivered messages
+	Failed  uint64 // delivery attempts having exhausted the retries
+	Retries uint64 // retried delivery attempts (the last failed attempt is not retried)
+	Dropped uint64 // messages lost: queue full without spool directory, rejected, or given up
+}
+
+// NewAsyncNotifier creates an AsyncNotifier sending the queued messages using the notifier.
+// queueSize bounds the number of pending messages in memory.
+// spoolDir is the directory storing the undelivered messages (empty disables the persistence).
+// The exported fields must be set before the first Notify (or Start) call.
+func NewAsyncNotifier(notifier Notifier, queueSize int, spoolDir string) *AsyncNotifier {
+	if notifier == nil {
+		log.Panic("gg.NewAsyncNotifier() requires a Notifier")
+	}
+	if queueSize < 1 {
+		queueSize = 1
+	}
+
+	a := &AsyncNotifier{
+		notifier:       notifier,
+		queue:          make(chan asyncItem, queueSize),
+		done:           make(chan struct{}),
+		spoolDir:       spoolDir,
+		MaxRetries:     defaultAsyncMaxRetries,
+		InitialBackoff: defaultAsyncInitialBackoff,
+		MaxBackoff:     defaultAsyncMaxBackoff,
+		MaxReplays:     defaultAsyncMaxReplays,
+		sent:           atomic.Uint64{},
+		failed:         atomic.Uint64{},
+		retries:        atomic.Uint64{},
+		dropped:        atomic.Uint64{},
+		spooled:        atomic.Int64{},
+		wg:             sync.WaitGroup{},
+		startOnce:      sync.Once{},
+		closeOnce:      sync.Once{},
+	}
+
+	if spoolDir != "" {
+		if err := os.MkdirAll(spoolDir, 0o700); err != nil {
+			log.Warn("AsyncNotifier: cannot create the spool directory, disable persistence:", err)
+			a.spoolDir = ""
+		}
+		a.spooled.Store(int64(len(a.spoolFiles())))
+	}
+
+	return a
+}
+
+// Start starts the background goroutines, also started by the first Notify call.
+// The messages spooled (by a previous run) are sent by a dedicated goroutine:
+// their retries do not delay the new messages.
+func (a *AsyncNotifier) Start() {
+	a.startOnce.Do(func() {
+		a.wg.Add(1)
+		go a.run()
+	})
+}
+
+// Notify enqueues the message and returns immediately.
+// When the queue is full, the message is spooled to disk (if enabled),
+// else Notify returns ErrQueueFull.
+func (a *AsyncNotifier) Notify(msg string) error {
+	return a.enqueue(asyncItem{msg: nil, text: msg})
+}
+
+// NotifyMessage is Notify for a structured message (see gg.NotifyMessage).
+func (a *AsyncNotifier) NotifyMessage(m Message) error {
+	return a.enqueue(asyncItem{msg: &m, text: ""})
+}
+
+func (a *AsyncNotifier) enqueue(it asyncItem) error {
+	a.Start()
+
+	select {
+	case <-a.done:
+		return a.spoolOrDrop(it)
+	default:
+	}
+
+	select {
+	case a.queue <- it:
+		return nil
+	default:
+		return a.spoolOrDrop(it)
+	}
+}
+
+// Close stops the background goroutine and spools the pending messages.
+// The messages within the queue are lost if the spool directory is disabled.
+func (a *AsyncNotifier) Close() error {
+	a.startOnce.Do(func() {}) // prevent starting after Close
+	a.closeOnce.Do(func() { close(a.done) })
+	a.wg.Wait()
+
+	var err error
+	for {
+		select {
+		case it := <-a.queue:
+			err = errors.Join(err, a.spoolOrDrop(it))
+		default:
+			return err
+		}
+	}
+}
+
+// Stats returns the current counters (e.g. to export metrics).
+func (a *AsyncNotifier) Stats() AsyncNotifierStats {
+	return AsyncNotifierStats{
+		Depth:   len(a.queue),
+		Spooled: a.spooled.Load(),
+		Sent:    a.sent.Load(),
+		Failed:  a.failed.Load(),
+		Retries: a.retries.Load(),
+		Dropped: a.dropped.Load(),
+	}
+}
+
+func (a *AsyncNotifier) run() {
+	defer a.wg.Done()
+
+	if a.spoolDir != "" {
+		// the files spooled before the start are listed now,
+		// the messages spooled later are sent at the next tick
+		a.wg.Add(1)
+		go a.replaySpool(a.spoolFiles())
+	}
+
+	for {
+		select {
+		case <-a.done:
+			return
+		case it := <-a.queue:
+			switch err := a.deliver(it); {
+			case err == nil:
+			case errors.Is(err, ErrRejected):
+				a.dropped.Add(1) // retrying later cannot fix it
+			default:
+				_ = a.spoolOrDrop(it)
+			}
+		}
+	}
+}
+
+// replaySpool sends the spooled messages at startup and then every MaxBackoff.
+// replaySpool runs in its own goroutine, apart from the live queue.
+func (a *AsyncNotifier) replaySpool(files []string) {
+	defer a.wg.Done()
+
+	a.sendSpooled(files)
+
+	ticker := time.NewTicker(a.MaxBackoff)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.done:
+			return
+		case <-ticker.C:
+			a.sendSpooled(a.spoolFiles())
+		}
+	}
+}
+
+// deliver sends the message with retries and returns the last error when undelivered.
+// deliver does not retry the messages rejected by the backend (see ErrRejected).
+func (a *AsyncNotifier) deliver(it asyncItem) error {
+	backoff := a.InitialBackoff
+	for attempt := 0; ; attempt++ {
+		err := it.send(a.notifier)
+		if err == nil {
+			a.sent.Add(1)
+			return nil
+		}
+
+		if attempt >= a.MaxRetries || errors.Is(err, ErrRejected) {
+			a.failed.Add(1)
+			log.Warnf("AsyncNotifier: give up after %d retries: %v", attempt, err)
+			return err
+		}
+		a.retries.Add(1)
+
+		select {
+		case <-a.done:
+			return err
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > a.MaxBackoff {
+			backoff = a.MaxBackoff
+		}
+	}
+}
+
+// sendSpooled sends the spooled files, the oldest first.
+// sendSpooled stops at the first transient failure (the backend is probably down),
+// and when the AsyncNotifier is closed.
+// The rejected messages (see ErrRejected) and the messages having failed MaxReplays
+// are given up: they do not block the next spooled messages.
+func (a *AsyncNotifier) sendSpooled(files []string) {
+	for _, fn := range files {
+		if a.closing() {
+			return
+		}
+
+		it, err := readSpooled(fn)
+		if err != nil {
+			log.Warn("AsyncNotifier: unreadable spooled message:", err)
+			a.giveUp(fn)
+			continue
+		}
+
+		err = a.deliver(it)
+		switch {
+		case err == nil:
+			if err = os.Remove(fn); err != nil {
+				log.Warn("AsyncNotifier:", err)
+			}
+			a.spooled.Add(-1)
+		case errors.Is(err, ErrRejected):
+			a.giveUp(fn)
+		case a.closing():
+			return // interrupted by Close: not a failed replay
+		default:
+			replays := spoolReplays(fn) + 1
+			if replays >= a.MaxReplays {
+				a.giveUp(fn)
+				continue
+			}
+			if err = os.Rename(fn, withSpoolReplays(fn, replays)); err != nil {
+				log.Warn("AsyncNotifier:", err)
+			}
+			return // transient failure: retry at the next tick
+		}
+	}
+}
+
+func (a *AsyncNotifier) closing() bool {
+	select {
+	case <-a.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// giveUp renames the spooled file as a dead letter "*.failed", no longer replayed.
+func (a *AsyncNotifier) giveUp(fn string) {
+	log.Warn("AsyncNotifier: give up the spooled message, see " + fn + deadLetterExt)
+	if err := os.Rename(fn, fn+deadLetterExt); err != nil {
+		log.Warn("AsyncNotifier:", err)
+	}
+	a.spooled.Add(-1)
+	a.dropped.Add(1)
+}
+
+// spoolReplays returns the number of failed replays encoded in the file name:
+// "<time>-<random><ext>" (no replay yet) or "<time>-<random>-<replays><ext>".
+func spoolReplays(fn string) int {
+	base := filepath.Base(fn)
+	base = base[:len(base)-len(filepath.Ext(base))]
+	parts := strings.Split(base, "-")
+	if len(parts) != 3 {
+		return 0
+	}
+	n, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// withSpoolReplays returns the file name with the updated number of failed replays.
+func withSpoolReplays(fn string, replays int) string {
+	ext := filepath.Ext(fn)
+	base := fn[:len(fn)-len(ext)]
+	if spoolReplays(fn) > 0 {
+		base = base[:strings.LastIndexByte(base, '-')]
+	}
+	return base + "-" + strconv.Itoa(replays) + ext
+}
+
+func (a *AsyncNotifier) spoolOrDrop(it asyncItem) error {
+	if a.spoolDir == "" {
+		a.dropped.Add(1)
+		return ErrQueueFull
+	}
+
+	buf, ext := []byte(it.text), spoolExt
+	if it.msg != nil {
+		var err error
+		buf, err = json.Marshal(it.msg)
+		if err != nil {
+			a.dropped.Add(1)
+			return errors.Join(ErrQueueFull, err)
+		}
+		ext = spoolMessageExt
+	}
+
+	name := strconv.FormatInt(time.Now().UnixNano(), 10) + "-" + hex.EncodeToString(RandomBytes(4)) + ext
+	fn := filepath.Join(a.spoolDir, name)
+	if err := os.WriteFile(fn, buf, 0o600); err != nil {
+		a.dropped.Add(1)
+		return errors.Join(ErrQueueFull, err)
+	}
+
+	a.spooled.Add(1)
+	return nil
+}
+
+// readSpooled reads a plain text message, or a structured Message (JSON).
+func readSpooled(fn string) (asyncItem, error) {
+	buf, err := os.ReadFile(fn)
+	if err != nil {
+		return asyncItem{}, err
+	}
+	if filepath.Ext(fn) != spoolMessageExt {
+		return asyncItem{msg: nil, text: string(buf)}, nil
+	}
+	var m Message
+	err = json.Unmarshal(buf, &m)
+	return asyncItem{msg: &m, text: ""}, err
+}
+
+// spoolFiles returns the spooled messages, the oldest first
+// (the file names start with the timestamp).
+func (a *AsyncNotifier) spoolFiles() []string {
+	if a.spoolDir == "" {
+		return nil
+	}
+	var files []string
+	for _, ext := range []string{spoolExt, spoolMessageExt} {
+		matches, err := filepath.Glob(filepath.Join(a.spoolDir, "*"+ext))
+		if err != nil {
+			log.Warn("AsyncNotifier:", err)
+			return nil
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+	return files
+}
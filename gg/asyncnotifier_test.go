@@ -0,0 +1,346 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package gg_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/teal-finance/garcon/gg"
+)
+
+// flakyNotifier fails the first `failures` calls.
+type flakyNotifier struct {
+	received []string
+	failures int
+	mu       sync.Mutex
+}
+
+func (n *flakyNotifier) Notify(msg string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.failures != 0 {
+		n.failures--
+		return errors.New("backend down")
+	}
+	n.received = append(n.received, msg)
+	return nil
+}
+
+func (n *flakyNotifier) messages() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]string{}, n.received...)
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if cond() {
+			return
+		}
+	}
+	t.Fatal("timeout")
+}
+
+func TestAsyncNotifier_Retry(t *testing.T) {
+	t.Parallel()
+
+	backend := &flakyNotifier{failures: 2}
+	a := gg.NewAsyncNotifier(backend, 10, "")
+	a.InitialBackoff = time.Millisecond
+	defer a.Close()
+
+	if err := a.Notify("hello"); err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor(t, func() bool { return a.Stats().Sent == 1 })
+
+	st := a.Stats()
+	if st.Retries != 2 || st.Failed != 0 || st.Dropped != 0 {
+		t.Errorf("unexpected stats %+v", st)
+	}
+	if got := backend.messages(); len(got) != 1 || got[0] != "hello" {
+		t.Errorf("received %q", got)
+	}
+}
+
+func TestAsyncNotifier_Spool(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	down := &flakyNotifier{failures: -1} // always fails
+	a := gg.NewAsyncNotifier(down, 10, dir)
+	a.MaxRetries = 0
+	a.MaxBackoff = time.Hour
+
+	for _, msg := range []string{"first", "second"} {
+		if err := a.Notify(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	waitFor(t, func() bool { return a.Stats().Failed == 2 })
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if st := a.Stats(); st.Spooled != 2 {
+		t.Fatalf("want 2 spooled messages, got %+v", st)
+	}
+
+	// restart: the spooled messages are sent in order
+	up := &flakyNotifier{}
+	b := gg.NewAsyncNotifier(up, 10, dir)
+	defer b.Close()
+	b.Start()
+
+	waitFor(t, func() bool { return b.Stats().Sent == 2 })
+
+	got := up.messages()
+	if len(got) != 2 || got[0] != "first" || got[1] != "second" {
+		t.Errorf("received %q", got)
+	}
+	if st := b.Stats(); st.Spooled != 0 {
+		t.Errorf("the spool should be empty: %+v", st)
+	}
+}
+
+// pickyNotifier fails the message "old" and counts the delivery attempts.
+type pickyNotifier struct {
+	flakyNotifier
+	attempts int
+}
+
+func (n *pickyNotifier) Notify(msg string) error {
+	n.mu.Lock()
+	n.attempts++
+	n.mu.Unlock()
+	if msg == "old" {
+		return errors.New("backend rejects old")
+	}
+	return n.flakyNotifier.Notify(msg)
+}
+
+func (n *pickyNotifier) attemptCount() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.attempts
+}
+
+func TestAsyncNotifier_MaxRetries(t *testing.T) {
+	t.Parallel()
+
+	backend := &pickyNotifier{}
+	a := gg.NewAsyncNotifier(backend, 10, "")
+	a.MaxRetries = 3
+	a.InitialBackoff = time.Millisecond
+	defer a.Close()
+
+	if err := a.Notify("old"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return a.Stats().Failed == 1 })
+
+	if n := backend.attemptCount(); n != 1+3 {
+		t.Errorf("MaxRetries=3 made %d attempts, want 4 (one attempt + 3 retries)", n)
+	}
+	if st := a.Stats(); st.Retries != 3 {
+		t.Errorf("want 3 retries, got %+v", st)
+	}
+}
+
+func TestAsyncNotifier_SpoolDoesNotBlockQueue(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	// spool an undelivered message
+	down := &flakyNotifier{failures: -1}
+	a := gg.NewAsyncNotifier(down, 10, dir)
+	a.MaxRetries = 0
+	a.MaxBackoff = time.Hour
+	if err := a.Notify("old"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return a.Stats().Failed == 1 })
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the spooled message is retried with a long backoff
+	backend := &pickyNotifier{}
+	b := gg.NewAsyncNotifier(backend, 10, dir)
+	b.InitialBackoff = time.Hour
+	b.MaxBackoff = time.Hour
+	defer b.Close()
+	b.Start()
+	waitFor(t, func() bool { return backend.attemptCount() == 1 }) // replay in progress
+
+	if err := b.Notify("live"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return b.Stats().Sent == 1 })
+
+	if got := backend.messages(); len(got) != 1 || got[0] != "live" {
+		t.Errorf("received %q", got)
+	}
+}
+
+// rejectingNotifier rejects the message "old" (see gg.ErrRejected).
+type rejectingNotifier struct {
+	flakyNotifier
+}
+
+func (n *rejectingNotifier) Notify(msg string) error {
+	if msg == "old" {
+		return fmt.Errorf("400 Bad Request: %w", gg.ErrRejected)
+	}
+	return n.flakyNotifier.Notify(msg)
+}
+
+// spoolUndelivered spools the messages using a backend always failing.
+func spoolUndelivered(t *testing.T, dir string, msgs ...string) {
+	t.Helper()
+
+	a := gg.NewAsyncNotifier(&flakyNotifier{failures: -1}, 10, dir)
+	a.MaxRetries = 0
+	a.MaxBackoff = time.Hour
+	for _, msg := range msgs {
+		if err := a.Notify(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitFor(t, func() bool { return a.Stats().Failed == uint64(len(msgs)) })
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAsyncNotifier_SpoolPoisonHead(t *testing.T) {
+	t.Parallel()
+
+	type recorder interface {
+		gg.Notifier
+		messages() []string
+	}
+
+	cases := []struct {
+		name    string
+		backend recorder
+	}{
+		{"rejected", &rejectingNotifier{}},   // given up at the first replay
+		{"always-failing", &pickyNotifier{}}, // given up after MaxReplays
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			spoolUndelivered(t, dir, "old", "good") // "old" is always undelivered
+
+			b := gg.NewAsyncNotifier(c.backend, 10, dir)
+			b.MaxRetries = 0
+			b.MaxReplays = 3
+			b.MaxBackoff = 10 * time.Millisecond
+			defer b.Close()
+			b.Start()
+
+			waitFor(t, func() bool { return b.Stats().Sent == 1 })
+			waitFor(t, func() bool { st := b.Stats(); return st.Spooled == 0 && st.Dropped == 1 })
+
+			if got := c.backend.messages(); len(got) != 1 || got[0] != "good" {
+				t.Errorf("received %q", got)
+			}
+			failed, _ := filepath.Glob(filepath.Join(dir, "*.failed"))
+			if len(failed) != 1 {
+				t.Errorf("want the undeliverable message as dead letter, got %q", failed)
+			} else if buf, _ := os.ReadFile(failed[0]); string(buf) != "old" {
+				t.Errorf("dead letter contains %q", buf)
+			}
+		})
+	}
+}
+
+// messageNotifier records the structured messages.
+type messageNotifier struct {
+	flakyNotifier
+	received []gg.Message
+}
+
+func (n *messageNotifier) NotifyMessage(m gg.Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.failures != 0 {
+		n.failures--
+		return errors.New("backend down")
+	}
+	n.received = append(n.received, m)
+	return nil
+}
+
+func (n *messageNotifier) structured() []gg.Message {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]gg.Message{}, n.received...)
+}
+
+func TestAsyncNotifier_NotifyMessage(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	m := gg.Message{Title: "Web form", Fields: []gg.Field{{Name: "name", Value: "*Bob*"}}, Severity: gg.SeverityInfo}
+
+	// the undelivered message is spooled as a structured message
+	down := &messageNotifier{flakyNotifier: flakyNotifier{failures: -1}}
+	a := gg.NewAsyncNotifier(down, 10, dir)
+	a.MaxRetries = 0
+	a.MaxBackoff = time.Hour
+	if err := a.NotifyMessage(m); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return a.Stats().Failed == 1 })
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	up := &messageNotifier{}
+	b := gg.NewAsyncNotifier(up, 10, dir)
+	defer b.Close()
+	b.Start()
+	waitFor(t, func() bool { return b.Stats().Sent == 1 })
+
+	got := up.structured()
+	if len(got) != 1 || got[0].Title != m.Title || len(got[0].Fields) != 1 || got[0].Fields[0] != m.Fields[0] {
+		t.Errorf("received %+v want %+v", got, m)
+	}
+	if len(up.messages()) != 0 {
+		t.Errorf("the message should not be rendered in plain text: %q", up.messages())
+	}
+}
+
+func TestAsyncNotifier_QueueFull(t *testing.T) {
+	t.Parallel()
+
+	a := gg.NewAsyncNotifier(&flakyNotifier{}, 1, "")
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.Notify("after close"); !errors.Is(err, gg.ErrQueueFull) {
+		t.Errorf("want ErrQueueFull, got %v", err)
+	}
+	if st := a.Stats(); st.Dropped != 1 {
+		t.Errorf("want 1 dropped message, got %+v", st)
+	}
+}
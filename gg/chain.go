@@ -168,11 +168,17 @@ func (c Chain) Then(handler http.Handler) http.Handler {
 // For proper middleware, this should cause no problems.
 //
 // Then() treats nil as http.DefaultTransport.
+//
+// Then() always inserts RTRequestID just before rt (even for an empty chain)
+// to propagate the request ID stored in the request context
+// (see RequestIDFromCtx) within the "X-Request-ID" header of the outgoing requests.
 func (c RTChain) Then(rt http.RoundTripper) http.RoundTripper {
 	if rt == nil {
 		rt = http.DefaultTransport
 	}
 
+	rt = RTRequestID(rt)
+
 	for i := range c {
 		middleware := c[len(c)-1-i]
 		if middleware != nil {
@@ -68,7 +68,21 @@ func TestChain_Then_WorksWithNoMiddleware(t *testing.T) {
 }
 
 func TestRTChain_Then_WorksWithNoMiddleware(t *testing.T) {
-	if !funcsEqual(gg.NewRTChain().Then(testRoundTripApp), testRoundTripApp) {
+	chained := gg.NewRTChain().Then(testRoundTripApp)
+
+	r, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = chained.RoundTrip(r); err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "app\n" {
 		t.Error("Then does not work with zero middleware")
 	}
 }
@@ -86,14 +100,33 @@ func TestChain_ThenFunc_TreatsNilAsDefaultServeMux(t *testing.T) {
 }
 
 func TestRTChain_Then_TreatsNilAsDefaultTransport(t *testing.T) {
-	if gg.NewRTChain().Then(nil) != http.DefaultTransport {
-		t.Error("Then does not treat nil as DefaultTransport")
-	}
+	testDefaultTransport(t, "Then", gg.NewRTChain().Then(nil))
 }
 
 func TestRTChain_ThenFunc_TreatsNilAsDefaultTransport(t *testing.T) {
-	if gg.NewRTChain().ThenFunc(nil) != http.DefaultTransport {
-		t.Error("ThenFunc does not treat nil as DefaultTransport")
+	testDefaultTransport(t, "ThenFunc", gg.NewRTChain().ThenFunc(nil))
+}
+
+// testDefaultTransport checks the round tripper sends the request to a real server.
+func testDefaultTransport(t *testing.T, name string, rt http.RoundTripper) {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	r, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := rt.RoundTrip(r)
+	if err != nil {
+		t.Fatalf("%s does not treat nil as DefaultTransport: %v", name, err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		t.Errorf("%s does not treat nil as DefaultTransport: status=%d", name, res.StatusCode)
 	}
 }
 
@@ -353,6 +386,59 @@ func TestRTChain_Append_RespectsImmutability2(t *testing.T) {
 	}
 }
 
+func TestRTChain_Then_PropagatesRequestID(t *testing.T) {
+	var got string
+	rt := gg.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		got = r.Header.Get(gg.RequestIDHeader)
+		var res http.Response
+		return &res, nil
+	})
+	chained := gg.NewRTChain(tagRTMiddleware("t1\n")).Then(rt)
+
+	ctx := gg.PutRequestID(context.Background(), "my-request-id")
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, "/", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := chained.RoundTrip(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Body != nil {
+		res.Body.Close()
+	}
+
+	if got != "my-request-id" {
+		t.Errorf("Then does not propagate the request ID, got %q", got)
+	}
+	if r.Header.Get(gg.RequestIDHeader) != "" {
+		t.Error("Then must not modify the original request")
+	}
+}
+
+func TestRTChain_Then_EmptyChainPropagatesRequestID(t *testing.T) {
+	var got string
+	rt := gg.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		got = r.Header.Get(gg.RequestIDHeader)
+		var res http.Response
+		return &res, nil
+	})
+
+	ctx := gg.PutRequestID(context.Background(), "my-request-id")
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, "/", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := gg.NewRTChain().Then(rt).RoundTrip(r); err != nil {
+		t.Fatal(err)
+	}
+	if got != "my-request-id" {
+		t.Errorf("empty chain does not propagate the request ID, got %q", got)
+	}
+}
+
 // tagMiddleware and tagRTMiddleware are constructors for middleware
 // that writes its own "tag" into the request body and does nothing else.
 // Useful in checking if a chain is behaving in the right order.
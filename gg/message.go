@@ -0,0 +1,309 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package gg
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+)
+
+// Message is a structured notification rendered for each platform by Render.
+type Message struct {
+	Title    string
+	Body     string // plain text, escaped by Render
+	Code     string // preformatted text (e.g. stack trace) displayed as a code block
+	Fields   []Field
+	Links    []Link
+	Severity Severity
+}
+
+// Field is a name/value pair displayed as a bullet list.
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Link is an hyperlink displayed at the end of the message.
+type Link struct {
+	Text string
+	URL  string
+}
+
+// Format is the markup dialect of the rendered Message.
+type Format int
+
+const (
+	FormatPlain    Format = iota // plain text, no markup
+	FormatMarkdown               // CommonMark (Mattermost, Discord)
+	FormatTelegram               // Telegram MarkdownV2
+	FormatSlack                  // Slack mrkdwn
+	FormatHTML                   // HTML (e-mail)
+)
+
+// Message size limits of the platforms, in characters
+// (in UTF-16 code units for Telegram, see SplitMessageUTF16).
+const (
+	TelegramMaxLen   = 4096
+	DiscordMaxLen    = 2000
+	SlackMaxLen      = 4000 // Slack truncates longer text
+	MattermostMaxLen = 16383
+)
+
+// MessageNotifier is implemented by the notifiers
+// able to render a Message with their own markup.
+type MessageNotifier interface {
+	NotifyMessage(m Message) error
+}
+
+// NotifyMessage sends the structured message using the markup of the notifier,
+// or in plain text if the notifier does not implement MessageNotifier.
+func NotifyMessage(n Notifier, m Message) error {
+	if mn, ok := n.(MessageNotifier); ok {
+		return mn.NotifyMessage(m)
+	}
+	return n.Notify(m.Render(FormatPlain))
+}
+
+// String returns the severity name, as parsed by ParseSeverity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityDebug:
+		return "debug"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return "info"
+	}
+}
+
+// Emoji returns a symbol representing the severity.
+func (s Severity) Emoji() string {
+	switch s {
+	case SeverityDebug:
+		return "🐞"
+	case SeverityWarning:
+		return "🟠"
+	case SeverityError:
+		return "🔴"
+	case SeverityCritical:
+		return "🚨"
+	default:
+		return "ℹ️"
+	}
+}
+
+// Render returns the message in the requested format, with the user content escaped.
+func (m Message) Render(f Format) string {
+	if f == FormatHTML {
+		return m.renderHTML()
+	}
+
+	esc, bold, link, bullet := m.markup(f)
+
+	var sb strings.Builder
+
+	if m.Title != "" {
+		sb.WriteString(bold(m.Severity.Emoji() + " " + esc(m.Title)))
+		sb.WriteString("\n\n")
+	}
+
+	for _, field := range m.Fields {
+		sb.WriteString(bullet + bold(esc(field.Name)) + esc(": ") + esc(field.Value) + "\n")
+	}
+	if len(m.Fields) > 0 {
+		sb.WriteString("\n")
+	}
+
+	if m.Body != "" {
+		sb.WriteString(esc(m.Body))
+		sb.WriteString("\n\n")
+	}
+
+	if m.Code != "" {
+		sb.WriteString(codeBlock(f, m.Code))
+		sb.WriteString("\n\n")
+	}
+
+	for i, l := range m.Links {
+		if i > 0 {
+			sb.WriteString(esc(" | "))
+		}
+		sb.WriteString(link(l))
+	}
+
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// markup returns the escaping function, the bold and link decorators and the bullet of the format.
+func (Message) markup(f Format) (esc func(string) string, bold func(string) string, link func(Link) string, bullet string) {
+	switch f {
+	case FormatMarkdown:
+		return EscapeMarkdown,
+			func(s string) string { return "**" + s + "**" },
+			func(l Link) string {
+				return "[" + EscapeMarkdown(l.Text) + "](<" + strings.ReplaceAll(l.URL, ">", "%3E") + ">)"
+			},
+			"- "
+	case FormatTelegram:
+		return EscapeTelegram,
+			func(s string) string { return "*" + s + "*" },
+			func(l Link) string { return "[" + EscapeTelegram(l.Text) + "](" + escapeTelegramURL(l.URL) + ")" },
+			"• "
+	case FormatSlack:
+		return EscapeSlack,
+			func(s string) string { return "*" + s + "*" },
+			func(l Link) string {
+				return "<" + EscapeSlack(l.URL) + "|" + EscapeSlack(strings.ReplaceAll(l.Text, "|", "¦")) + ">"
+			},
+			"• "
+	default:
+		return func(s string) string { return s },
+			func(s string) string { return s },
+			func(l Link) string { return l.Text + ": " + l.URL },
+			"- "
+	}
+}
+
+// codeBlock returns the preformatted text as a code block of the format.
+func codeBlock(f Format, code string) string {
+	code = strings.TrimRight(code, "\n")
+	switch f {
+	case FormatMarkdown:
+		fence := "```"
+		for strings.Contains(code, fence) {
+			fence += "`" // the code cannot close the block
+		}
+		return fence + "\n" + code + "\n" + fence
+	case FormatTelegram:
+		return "```\n" + strings.NewReplacer(`\`, `\\`, "`", "\\`").Replace(code) + "\n```"
+	case FormatSlack:
+		return "```\n" + EscapeSlack(code) + "\n```"
+	default:
+		return code
+	}
+}
+
+func (m Message) renderHTML() string {
+	var buf bytes.Buffer
+	// goldmark omits the raw HTML by default, and the user content is escaped by EscapeMarkdown
+	if err := goldmark.Convert([]byte(m.Render(FormatMarkdown)), &buf); err != nil {
+		log.Warn("Message: cannot convert Markdown to HTML:", err)
+		return "<pre>" + strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(m.Render(FormatPlain)) + "</pre>"
+	}
+	return buf.String()
+}
+
+// EscapeMarkdown escapes the CommonMark punctuation (Mattermost, Discord...).
+func EscapeMarkdown(s string) string {
+	return escapeWith(s, "\\`*_{}[]()#+-.!|<>~")
+}
+
+// EscapeTelegram escapes the characters reserved by the Telegram MarkdownV2.
+func EscapeTelegram(s string) string {
+	return escapeWith(s, "\\_*[]()~`>#+-=|{}.!")
+}
+
+// escapeTelegramURL escapes the characters ")" and "\" within the link URL (Telegram MarkdownV2).
+func escapeTelegramURL(u string) string {
+	return strings.NewReplacer(`\`, `\\`, `)`, `\)`).Replace(u)
+}
+
+// EscapeSlack escapes the control characters of the Slack mrkdwn.
+func EscapeSlack(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+func escapeWith(s, special string) string {
+	var sb strings.Builder
+	sb.Grow(len(s) + len(s)/8)
+	for _, r := range s {
+		if r < utf8.RuneSelf && strings.ContainsRune(special, r) {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// SplitMessage splits the text in chunks of at most maxLen characters (runes).
+// SplitMessage cuts preferably at a line break, else at a space,
+// and never just after an escaping backslash.
+func SplitMessage(text string, maxLen int) []string {
+	return splitMessage(text, maxLen, func(rune) int { return 1 })
+}
+
+// SplitMessageUTF16 is SplitMessage counting the UTF-16 code units,
+// as the Telegram limit: the characters outside the BMP (most emojis) count for two.
+func SplitMessageUTF16(text string, maxLen int) []string {
+	return splitMessage(text, maxLen, func(r rune) int {
+		if r >= 0x10000 {
+			return 2 // surrogate pair
+		}
+		return 1
+	})
+}
+
+func splitMessage(text string, maxLen int, width func(rune) int) []string {
+	if maxLen <= 0 || prefixLen(text, maxLen, width) == len(text) {
+		return []string{text}
+	}
+
+	var chunks []string
+	for text != "" {
+		// byte offset of the longest prefix within maxLen
+		limit := prefixLen(text, maxLen, width)
+		if limit == len(text) {
+			chunks = append(chunks, text)
+			break
+		}
+		if limit == 0 {
+			_, limit = utf8.DecodeRuneInString(text) // maxLen smaller than one character
+		}
+
+		cut := strings.LastIndexByte(text[:limit], '\n')
+		if cut <= 0 {
+			cut = strings.LastIndexByte(text[:limit], ' ')
+		}
+		if cut <= 0 {
+			cut = limit
+			if cut > 1 && escaped(text[:cut]) {
+				cut-- // do not separate the backslash from the escaped character
+			}
+		}
+
+		chunks = append(chunks, strings.TrimRight(text[:cut], " \n"))
+		text = strings.TrimLeft(text[cut:], " \n")
+	}
+	return chunks
+}
+
+// prefixLen returns the byte length of the longest prefix of text within maxLen.
+func prefixLen(text string, maxLen int, width func(rune) int) int {
+	n := 0
+	for i, r := range text {
+		n += width(r)
+		if n > maxLen {
+			return i
+		}
+	}
+	return len(text)
+}
+
+// escaped returns true if s ends with an odd number of backslashes.
+func escaped(s string) bool {
+	n := 0
+	for i := len(s) - 1; i >= 0 && s[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
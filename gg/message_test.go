@@ -0,0 +1,189 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package gg_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/teal-finance/garcon/gg"
+)
+
+func testMessage() gg.Message {
+	return gg.Message{
+		Title:    "Disk 95% full!",
+		Body:     "Free some space <now> & check *logs*_v2.",
+		Fields:   []gg.Field{{Name: "host", Value: "db-1.example.com"}},
+		Links:    []gg.Link{{Text: "Grafana (disk)", URL: "https://grafana.example.com/d/x?a=(1)"}},
+		Severity: gg.SeverityError,
+	}
+}
+
+func TestMessage_Render(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		want   string
+		format gg.Format
+	}{
+		{
+			"plain",
+			"🔴 Disk 95% full!\n\n- host: db-1.example.com\n\nFree some space <now> & check *logs*_v2.\n\nGrafana (disk): https://grafana.example.com/d/x?a=(1)",
+			gg.FormatPlain,
+		},
+		{
+			"markdown",
+			"**🔴 Disk 95% full\\!**\n\n- **host**: db\\-1\\.example\\.com\n\nFree some space \\<now\\> & check \\*logs\\*\\_v2\\.\n\n[Grafana \\(disk\\)](<https://grafana.example.com/d/x?a=(1)>)",
+			gg.FormatMarkdown,
+		},
+		{
+			"telegram",
+			"*🔴 Disk 95% full\\!*\n\n• *host*: db\\-1\\.example\\.com\n\nFree some space <now\\> & check \\*logs\\*\\_v2\\.\n\n[Grafana \\(disk\\)](https://grafana.example.com/d/x?a=(1\\))",
+			gg.FormatTelegram,
+		},
+		{
+			"slack",
+			"*🔴 Disk 95% full!*\n\n• *host*: db-1.example.com\n\nFree some space &lt;now&gt; &amp; check *logs*_v2.\n\n<https://grafana.example.com/d/x?a=(1)|Grafana (disk)>",
+			gg.FormatSlack,
+		},
+	}
+
+	m := testMessage()
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			if got := m.Render(c.format); got != c.want {
+				t.Errorf("Render()\ngot  %q\nwant %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestMessage_RenderHTML(t *testing.T) {
+	t.Parallel()
+
+	got := testMessage().Render(gg.FormatHTML)
+	for _, want := range []string{
+		"<strong>🔴 Disk 95% full!</strong>",
+		"<li><strong>host</strong>: db-1.example.com</li>",
+		"Free some space &lt;now&gt; &amp; check *logs*_v2.",
+		`<a href="https://grafana.example.com/d/x?a=(1)">Grafana (disk)</a>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in %q", want, got)
+		}
+	}
+	if strings.Contains(got, "<now>") {
+		t.Errorf("raw HTML not escaped: %q", got)
+	}
+}
+
+func TestMessage_RenderCode(t *testing.T) {
+	t.Parallel()
+
+	m := gg.Message{Title: "Panic", Body: "boom", Code: "main.go:12 <a> ```\\\n", Severity: gg.SeverityCritical}
+
+	cases := []struct {
+		name   string
+		want   string
+		format gg.Format
+	}{
+		{"plain", "🚨 Panic\n\nboom\n\nmain.go:12 <a> ```\\", gg.FormatPlain},
+		{"markdown", "**🚨 Panic**\n\nboom\n\n````\nmain.go:12 <a> ```\\\n````", gg.FormatMarkdown},
+		{"telegram", "*🚨 Panic*\n\nboom\n\n```\nmain.go:12 <a> \\`\\`\\`\\\\\n```", gg.FormatTelegram},
+		{"slack", "*🚨 Panic*\n\nboom\n\n```\nmain.go:12 &lt;a&gt; ```\\\n```", gg.FormatSlack},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			if got := m.Render(c.format); got != c.want {
+				t.Errorf("Render()\ngot  %q\nwant %q", got, c.want)
+			}
+		})
+	}
+
+	if got := m.Render(gg.FormatHTML); !strings.Contains(got, "<pre><code>main.go:12 &lt;a&gt; ```\\\n</code></pre>") {
+		t.Errorf("missing the code block in %q", got)
+	}
+}
+
+func TestSplitMessage(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		text   string
+		want   []string
+		maxLen int
+	}{
+		{"short", "hello", []string{"hello"}, 10},
+		{"lines", "aaa\nbbb\nccc", []string{"aaa\nbbb", "ccc"}, 8},
+		{"words", "aaa bbb ccc", []string{"aaa bbb", "ccc"}, 8},
+		{"runes", "ééééé", []string{"ééé", "éé"}, 3},
+		{"escape", `ab\.cd`, []string{`ab`, `\.c`, `d`}, 3},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			got := gg.SplitMessage(c.text, c.maxLen)
+			if strings.Join(got, "|") != strings.Join(c.want, "|") {
+				t.Errorf("SplitMessage(%q, %d) = %q want %q", c.text, c.maxLen, got, c.want)
+			}
+			for _, chunk := range got {
+				if utf8.RuneCountInString(chunk) > c.maxLen {
+					t.Errorf("chunk %q exceeds %d", chunk, c.maxLen)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitMessageUTF16(t *testing.T) {
+	t.Parallel()
+
+	// the emojis are surrogate pairs: two UTF-16 code units
+	got := gg.SplitMessageUTF16("😀😀😀 é", 4)
+	if want := []string{"😀😀", "😀 é"}; strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("SplitMessageUTF16 = %q want %q", got, want)
+	}
+}
+
+func TestTelegramNotifier_NotifyMessage(t *testing.T) {
+	t.Parallel()
+
+	var texts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("parse_mode") != "MarkdownV2" {
+			t.Errorf("parse_mode=%q", r.FormValue("parse_mode"))
+		}
+		texts = append(texts, r.FormValue("text"))
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	m := testMessage()
+	m.Body = strings.Repeat("Lorem ipsum dolor sit amet.\n", 200) // > 4096 characters
+
+	n := gg.NewTelegramNotifier(srv.URL, "42")
+	if err := gg.NotifyMessage(n, m); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(texts) != 2 {
+		t.Fatalf("got %d messages, want 2", len(texts))
+	}
+	for _, txt := range texts {
+		if utf8.RuneCountInString(txt) > gg.TelegramMaxLen {
+			t.Errorf("message length %d exceeds the Telegram limit", utf8.RuneCountInString(txt))
+		}
+	}
+}
@@ -0,0 +1,262 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package gg
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// NotifyError aggregates the failures of several Notifier backends.
+// errors.Is and errors.As inspect every failure.
+type NotifyError struct {
+	Failures []BackendFailure
+}
+
+// BackendFailure is the failure of one backend within NotifyError.
+type BackendFailure struct {
+	Err     error
+	Backend string // index and type of the Notifier, e.g. "#2 gg.SlackNotifier"
+}
+
+func (e *NotifyError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(strconv.Itoa(len(e.Failures)) + " notifier(s) failed:")
+	for _, f := range e.Failures {
+		sb.WriteString(" [" + f.Backend + "] " + f.Err.Error() + ";")
+	}
+	return strings.TrimSuffix(sb.String(), ";")
+}
+
+func (e *NotifyError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, f := range e.Failures {
+		errs[i] = f.Err
+	}
+	return errs
+}
+
+// MultiNotifier fans out the messages to several backends.
+type MultiNotifier []Notifier
+
+// NewMultiNotifier creates a MultiNotifier, the nil notifiers are ignored.
+func NewMultiNotifier(notifiers ...Notifier) MultiNotifier {
+	m := make(MultiNotifier, 0, len(notifiers))
+	for _, n := range notifiers {
+		if n != nil {
+			m = append(m, n)
+		}
+	}
+	return m
+}
+
+// Notify sends the message to all the backends concurrently.
+// Notify returns a *NotifyError reporting the failed backends, or nil if all succeeded.
+func (m MultiNotifier) Notify(msg string) error {
+	return m.fanOut(func(n Notifier) error { return n.Notify(msg) })
+}
+
+// NotifyMessage sends the structured message to all the backends concurrently,
+// each backend rendering the message with its own markup (see NotifyMessage).
+func (m MultiNotifier) NotifyMessage(msg Message) error {
+	return m.fanOut(func(n Notifier) error { return NotifyMessage(n, msg) })
+}
+
+func (m MultiNotifier) fanOut(send func(Notifier) error) error {
+	switch len(m) {
+	case 0:
+		return nil
+	case 1:
+		if err := send(m[0]); err != nil {
+			return &NotifyError{Failures: []BackendFailure{{Err: err, Backend: backendName(0, m[0])}}}
+		}
+		return nil
+	}
+
+	errs := make([]error, len(m))
+	var wg sync.WaitGroup
+	for i, n := range m {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = send(n)
+		}()
+	}
+	wg.Wait()
+
+	var failures []BackendFailure
+	for i, err := range errs {
+		if err != nil {
+			failures = append(failures, BackendFailure{Err: err, Backend: backendName(i, m[i])})
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	return &NotifyError{Failures: failures}
+}
+
+func backendName(i int, n Notifier) string {
+	return "#" + strconv.Itoa(i+1) + " " + strings.TrimPrefix(fmt.Sprintf("%T", n), "*")
+}
+
+// Severity of a message, parsed by ParseSeverity.
+type Severity int
+
+const (
+	SeverityDebug Severity = iota
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+// ParseSeverity reads the severity at the beginning of the message:
+// "[ERROR] ...", "error: ...", "WARN ..." (case-insensitive).
+// ParseSeverity returns SeverityInfo by default.
+func ParseSeverity(msg string) Severity {
+	word := strings.TrimLeft(msg, " \t[")
+	if i := strings.IndexAny(word, " \t]:"); i >= 0 {
+		word = word[:i]
+	}
+
+	switch strings.ToLower(word) {
+	case "debug", "trace":
+		return SeverityDebug
+	case "warn", "warning":
+		return SeverityWarning
+	case "error", "err":
+		return SeverityError
+	case "critical", "crit", "fatal", "panic", "alert", "emergency":
+		return SeverityCritical
+	default:
+		return SeverityInfo
+	}
+}
+
+// HasTag returns true if the message contains the hashtag "#tag",
+// delimited on both sides: "#db" matches "down #db" but neither "foo#db" nor "#dbx".
+func HasTag(msg, tag string) bool {
+	tag = "#" + strings.TrimPrefix(tag, "#")
+	for i := 0; i <= len(msg)-len(tag); {
+		j := strings.Index(msg[i:], tag)
+		if j < 0 {
+			break
+		}
+		start := i + j
+		end := start + len(tag)
+		if (start == 0 || !isTagRune(msg[start-1])) && (end == len(msg) || !isTagRune(msg[end])) {
+			return true
+		}
+		i = start + 1
+	}
+	return false
+}
+
+func isTagRune(c byte) bool {
+	return c == '_' || c == '-' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// RouterNotifier selects the backends depending on the message:
+// severity, hashtag, regular expression or any custom function.
+// A message matching several routes is sent to the backends of all these routes.
+// A message matching no route is sent to the Fallback (if not nil).
+//
+//	router := gg.NewRouterNotifier(logNotifier).
+//		Tag("contact", telegram, email).
+//		Severity(gg.SeverityError, mattermost, logFile)
+type RouterNotifier struct {
+	Fallback Notifier
+	routes   []route
+}
+
+type route struct {
+	match     func(msg string) bool
+	notifiers MultiNotifier
+}
+
+// NewRouterNotifier creates a RouterNotifier without any route.
+func NewRouterNotifier(fallback Notifier) *RouterNotifier {
+	return &RouterNotifier{Fallback: fallback, routes: nil}
+}
+
+// Match adds a route selected by a custom function.
+func (r *RouterNotifier) Match(match func(msg string) bool, notifiers ...Notifier) *RouterNotifier {
+	r.routes = append(r.routes, route{match: match, notifiers: NewMultiNotifier(notifiers...)})
+	return r
+}
+
+// Severity adds a route for the messages having at least the given severity (see ParseSeverity).
+func (r *RouterNotifier) Severity(minimum Severity, notifiers ...Notifier) *RouterNotifier {
+	return r.Match(func(msg string) bool { return ParseSeverity(msg) >= minimum }, notifiers...)
+}
+
+// Tag adds a route for the messages containing the hashtag (e.g. "#contact").
+func (r *RouterNotifier) Tag(tag string, notifiers ...Notifier) *RouterNotifier {
+	return r.Match(func(msg string) bool { return HasTag(msg, tag) }, notifiers...)
+}
+
+// Regexp adds a route for the messages matching the regular expression.
+// Regexp panics if the expression cannot be compiled.
+func (r *RouterNotifier) Regexp(expr string, notifiers ...Notifier) *RouterNotifier {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		log.Panicf("gg.RouterNotifier.Regexp(%q): %v", expr, err)
+	}
+	return r.Match(re.MatchString, notifiers...)
+}
+
+// Notify sends the message to the backends of all the matching routes.
+// Notify returns a *NotifyError reporting the failed backends, or nil if all succeeded.
+func (r *RouterNotifier) Notify(msg string) error {
+	return r.selectFor(msg).Notify(msg)
+}
+
+// NotifyMessage sends the structured message to the backends of all the matching routes,
+// each backend rendering the message with its own markup (see NotifyMessage).
+// The routes match the severity name followed by the plain text rendering,
+// e.g. "error: 🔴 DB down".
+func (r *RouterNotifier) NotifyMessage(msg Message) error {
+	return r.selectFor(msg.Severity.String() + ": " + msg.Render(FormatPlain)).NotifyMessage(msg)
+}
+
+func (r *RouterNotifier) selectFor(msg string) MultiNotifier {
+	var selected MultiNotifier
+	for _, rt := range r.routes {
+		if !rt.match(msg) {
+			continue
+		}
+		for _, n := range rt.notifiers {
+			if !containsNotifier(selected, n) {
+				selected = append(selected, n)
+			}
+		}
+	}
+
+	if len(selected) == 0 && r.Fallback != nil {
+		selected = MultiNotifier{r.Fallback}
+	}
+	return selected
+}
+
+// containsNotifier returns true if the backend is already selected
+// (a backend listed in several matching routes receives the message only once).
+// The non-comparable notifiers (e.g. MultiNotifier) are never considered duplicated.
+func containsNotifier(notifiers MultiNotifier, n Notifier) bool {
+	if n == nil || !reflect.TypeOf(n).Comparable() {
+		return false
+	}
+	for _, s := range notifiers {
+		if s != nil && reflect.TypeOf(s) == reflect.TypeOf(n) && s == n {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package gg_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/teal-finance/garcon/gg"
+)
+
+var errDown = errors.New("backend down")
+
+type downNotifier struct{}
+
+func (downNotifier) Notify(string) error { return errDown }
+
+func TestMultiNotifier(t *testing.T) {
+	t.Parallel()
+
+	a, b := &flakyNotifier{}, &flakyNotifier{}
+	m := gg.NewMultiNotifier(a, nil, downNotifier{}, b)
+
+	err := m.Notify("hello")
+
+	var ne *gg.NotifyError
+	if !errors.As(err, &ne) || len(ne.Failures) != 1 {
+		t.Fatalf("want one failure, got %v", err)
+	}
+	if ne.Failures[0].Backend != "#2 gg_test.downNotifier" {
+		t.Errorf("Backend=%q", ne.Failures[0].Backend)
+	}
+	if !errors.Is(err, errDown) {
+		t.Error("errors.Is should find the backend error")
+	}
+	if len(a.messages()) != 1 || len(b.messages()) != 1 {
+		t.Error("all the backends should receive the message")
+	}
+}
+
+func TestRouterNotifier(t *testing.T) {
+	t.Parallel()
+
+	fallback, contact, errs, db := &flakyNotifier{}, &flakyNotifier{}, &flakyNotifier{}, &flakyNotifier{}
+	router := gg.NewRouterNotifier(fallback).
+		Tag("contact", contact).
+		Severity(gg.SeverityError, errs).
+		Regexp(`(?i)postgres|database`, db)
+
+	for _, msg := range []string{
+		"New message #contact from the website",
+		"#contacts is not #contact-form",
+		"[ERROR] cannot connect to the database",
+		"critical: disk full",
+		"Info: started",
+	} {
+		if err := router.Notify(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	check := func(name string, n *flakyNotifier, want ...string) {
+		t.Helper()
+		if got := n.messages(); strings.Join(got, "|") != strings.Join(want, "|") {
+			t.Errorf("%s received %q want %q", name, got, want)
+		}
+	}
+	check("contact", contact, "New message #contact from the website")
+	check("errors", errs, "[ERROR] cannot connect to the database", "critical: disk full")
+	check("database", db, "[ERROR] cannot connect to the database")
+	check("fallback", fallback, "#contacts is not #contact-form", "Info: started")
+}
+
+func TestRouterNotifier_NotifyMessage(t *testing.T) {
+	t.Parallel()
+
+	fallback, errs := &messageNotifier{}, &messageNotifier{}
+	router := gg.NewRouterNotifier(fallback).Severity(gg.SeverityError, errs)
+
+	for _, s := range []gg.Severity{gg.SeverityCritical, gg.SeverityInfo} {
+		if err := router.NotifyMessage(gg.Message{Title: "DB down", Severity: s}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := errs.structured(); len(got) != 1 || got[0].Severity != gg.SeverityCritical {
+		t.Errorf("errors received %+v", got)
+	}
+	if got := fallback.structured(); len(got) != 1 || got[0].Severity != gg.SeverityInfo {
+		t.Errorf("fallback received %+v", got)
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]gg.Severity{
+		"[WARN] slow":    gg.SeverityWarning,
+		"error: timeout": gg.SeverityError,
+		"Fatal crash":    gg.SeverityCritical,
+		"debug":          gg.SeverityDebug,
+		"errors happen":  gg.SeverityInfo,
+		"":               gg.SeverityInfo,
+	}
+	for msg, want := range cases {
+		if got := gg.ParseSeverity(msg); got != want {
+			t.Errorf("ParseSeverity(%q) = %d want %d", msg, got, want)
+		}
+	}
+}
+
+func TestRouterNotifier_Dedupe(t *testing.T) {
+	t.Parallel()
+
+	fallback, ops := &flakyNotifier{}, &flakyNotifier{}
+	router := gg.NewRouterNotifier(fallback).
+		Tag("db", ops).
+		Severity(gg.SeverityError, ops)
+
+	if err := router.Notify("[ERROR] replica lag #db"); err != nil {
+		t.Fatal(err)
+	}
+	if got := ops.messages(); len(got) != 1 {
+		t.Errorf("backend of several matching routes received %d messages, want 1: %q", len(got), got)
+	}
+}
+
+func TestHasTag(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		msg  string
+		want bool
+	}{
+		{"#db", true},
+		{"down #db", true},
+		{"down (#db)", true},
+		{"#db: replica lag", true},
+		{"foo#db", false},
+		{"a#db", false},
+		{"#dbx", false},
+		{"#db-replica", false},
+		{"foo#db #db", true},
+		{"", false},
+	}
+	for _, c := range cases {
+		if got := gg.HasTag(c.msg, "db"); got != c.want {
+			t.Errorf("HasTag(%q) = %v want %v", c.msg, got, c.want)
+		}
+	}
+}
@@ -20,7 +20,10 @@ type Notifier interface {
 	Notify(message string) error
 }
 
-// NewNotifier selects the Notifier type depending on the parameter pattern.
+// NewNotifier selects the Notifier type depending on the parameter pattern:
+// empty => LogNotifier, Telegram URL => TelegramNotifier,
+// scheme slack:// discord:// ntfy:// smtp:// webhook+https:// (see notifierFromScheme),
+// otherwise MattermostNotifier.
 func NewNotifier(dataSourceName string) Notifier {
 	if dataSourceName == "" {
 		log.Info("empty dataSourceName => use the LogNotifier")
@@ -39,6 +42,11 @@ func NewNotifier(dataSourceName string) Notifier {
 		return NewLogNotifier()
 	}
 
+	if n := notifierFromScheme(dataSourceName); n != nil {
+		log.Info("Notifier selected from the scheme: " + strings.SplitN(dataSourceName, ":", 2)[0])
+		return n
+	}
+
 	// default
 	return NewMattermostNotifier(dataSourceName)
 }
@@ -82,11 +90,17 @@ func (n MattermostNotifier) Notify(msg string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("MattermostNotifier: %s from host=%s", resp.Status, n.host())
+		return responseErr("MattermostNotifier", resp, n.host())
 	}
 	return nil
 }
 
+// NotifyMessage sends the message rendered in Markdown,
+// split in several posts when exceeding the Mattermost limit.
+func (n MattermostNotifier) NotifyMessage(m Message) error {
+	return notifyChunks(n, m.Render(FormatMarkdown), MattermostMaxLen)
+}
+
 func (n MattermostNotifier) host() string {
 	u, err := url.Parse(n.endpoint)
 	if err == nil {
@@ -109,14 +123,37 @@ func NewTelegramNotifier(endpoint, chatID string) TelegramNotifier {
 	}
 }
 
+// ChatID returns the Telegram chat room receiving the messages.
+func (n TelegramNotifier) ChatID() string {
+	return n.chatID
+}
+
 // Notify sends a message to the Telegram server.
 func (n TelegramNotifier) Notify(msg string) error {
-	response, err := http.PostForm(
-		n.endpoint,
-		url.Values{
-			"chat_id": {n.chatID},
-			"text":    {msg},
-		})
+	return n.send(msg, "")
+}
+
+// NotifyMessage sends the message rendered in MarkdownV2,
+// split in several messages when exceeding the Telegram limit.
+func (n TelegramNotifier) NotifyMessage(m Message) error {
+	for _, chunk := range SplitMessageUTF16(m.Render(FormatTelegram), TelegramMaxLen) {
+		if err := n.send(chunk, "MarkdownV2"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n TelegramNotifier) send(text, parseMode string) error {
+	form := url.Values{
+		"chat_id": {n.chatID},
+		"text":    {text},
+	}
+	if parseMode != "" {
+		form.Set("parse_mode", parseMode)
+	}
+
+	response, err := http.PostForm(n.endpoint, form)
 	if err != nil {
 		return fmt.Errorf("TelegramNotifier chat_id=%s: %w", n.chatID, err)
 	}
@@ -129,7 +166,11 @@ func (n TelegramNotifier) Notify(msg string) error {
 	}
 
 	if !resp.Ok {
-		return fmt.Errorf("TelegramNotifier chat_id=%s: sending failed", n.chatID)
+		err = fmt.Errorf("TelegramNotifier chat_id=%s: sending failed", n.chatID)
+		if rejected(response.StatusCode) {
+			err = fmt.Errorf("%w: %w", err, ErrRejected) // e.g. invalid MarkdownV2
+		}
+		return err
 	}
 
 	return nil
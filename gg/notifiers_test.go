@@ -0,0 +1,209 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package gg_test
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/teal-finance/garcon/gg"
+)
+
+func TestNotifiers_HTTP(t *testing.T) {
+	t.Parallel()
+
+	type received struct {
+		header http.Header
+		path   string
+		body   string
+	}
+	ch := make(chan received, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ch <- received{r.Header, r.URL.Path, string(body)}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	cases := []struct {
+		dsn    string
+		path   string
+		body   string
+		header [2]string
+	}{
+		{"slack+http://" + host + "/services/T/B/X", "/services/T/B/X", `{"text":"Hello \"world\""}`, [2]string{"Content-Type", "application/json"}},
+		{"discord+http://" + host + "/api/webhooks/1/tok", "/api/webhooks/1/tok", `{"content":"Hello \"world\""}`, [2]string{"Content-Type", "application/json"}},
+		{"mattermost+http://" + host + "/hooks/xxx", "/hooks/xxx", `{"text":"Hello \"world\""}`, [2]string{"Content-Type", "application/json"}},
+		{"ntfy+http://:secret@" + host + "/alerts?title=API&priority=high", "/alerts", `Hello "world"`, [2]string{"Authorization", "Bearer secret"}},
+		{"webhook+http://" + host + "/hook?template=" + url.QueryEscape(`{"msg":{{json .Message}},"v":1}`), "/hook", `{"msg":"Hello \"world\"","v":1}`, [2]string{"Content-Type", "application/json"}},
+	}
+
+	for _, c := range cases {
+		n := gg.NewNotifier(c.dsn)
+		if err := n.Notify(`Hello "world"`); err != nil {
+			t.Fatalf("%s: %v", c.dsn, err)
+		}
+
+		got := <-ch
+		if got.path != c.path {
+			t.Errorf("%s: path=%q want %q", c.dsn, got.path, c.path)
+		}
+		if got.body != c.body {
+			t.Errorf("%s: body=%s want %s", c.dsn, got.body, c.body)
+		}
+		if v := got.header.Get(c.header[0]); v != c.header[1] {
+			t.Errorf("%s: header %s=%q want %q", c.dsn, c.header[0], v, c.header[1])
+		}
+		if strings.HasPrefix(c.dsn, "ntfy") && got.header.Get("Title") != "API" {
+			t.Errorf("%s: missing the Title header", c.dsn)
+		}
+	}
+}
+
+func TestNotifiers_Rejected(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		status   int
+		rejected bool
+	}{
+		{http.StatusBadRequest, true},
+		{http.StatusRequestEntityTooLarge, true},
+		{http.StatusTooManyRequests, false},
+		{http.StatusRequestTimeout, false},
+		{http.StatusServiceUnavailable, false},
+	}
+
+	for _, c := range cases {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(c.status)
+		}))
+		host := strings.TrimPrefix(srv.URL, "http://")
+
+		for _, dsn := range []string{"slack+http://" + host + "/s", "mattermost+http://" + host + "/hooks/x"} {
+			err := gg.NewNotifier(dsn).Notify("hello")
+			if err == nil {
+				t.Errorf("%s status=%d: want an error", dsn, c.status)
+				continue
+			}
+			if got := errors.Is(err, gg.ErrRejected); got != c.rejected {
+				t.Errorf("%s status=%d: errors.Is(err, ErrRejected)=%v want %v (%v)", dsn, c.status, got, c.rejected, err)
+			}
+		}
+		srv.Close()
+	}
+}
+
+func TestNotifiers_SMTP(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	mail := make(chan string, 1)
+	go fakeSMTPServer(ln, mail)
+
+	dsn := "smtp://api%40example.com:pass@" + ln.Addr().String() + "/?to=ops@example.com,dev@example.com&subject=Alert"
+	n := gg.NewNotifier(dsn)
+	if err = n.Notify("line 1\nline 2"); err != nil {
+		t.Fatal(err)
+	}
+
+	got := <-mail
+	for _, want := range []string{
+		"MAIL FROM:<api@example.com>",
+		"RCPT TO:<ops@example.com>",
+		"RCPT TO:<dev@example.com>",
+		"AUTH PLAIN",
+		"Subject: Alert\r\n",
+		"\r\n\r\nline 1\r\nline 2\r\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in %q", want, got)
+		}
+	}
+}
+
+func TestNotifiers_SMTPS(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go fakeSMTPServer(ln, make(chan string, 1))
+
+	// smtps starts with the TLS handshake: the plain text server is rejected without hanging
+	n, err := gg.NewSMTPNotifier("smtps://" + ln.Addr().String() + "/?to=ops@example.com&from=api@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = n.Notify("hello")
+	if err == nil || !strings.Contains(err.Error(), "tls:") {
+		t.Errorf("want a TLS handshake error, got %v", err)
+	}
+}
+
+// fakeSMTPServer handles one SMTP session and sends the whole transcript to the mail channel.
+func fakeSMTPServer(ln net.Listener, mail chan<- string) {
+	conn, err := ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	var transcript strings.Builder
+	r := bufio.NewReader(conn)
+	reply := func(s string) { io.WriteString(conn, s+"\r\n") }
+
+	reply("220 localhost fake SMTP")
+	data := false
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			break
+		}
+		transcript.WriteString(line)
+
+		switch {
+		case data:
+			if line == ".\r\n" {
+				data = false
+				reply("250 OK")
+			}
+		case strings.HasPrefix(line, "EHLO"):
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case strings.HasPrefix(line, "AUTH"):
+			reply("235 Authentication successful")
+		case strings.HasPrefix(line, "DATA"):
+			data = true
+			reply("354 End data with <CR><LF>.<CR><LF>")
+		case strings.HasPrefix(line, "QUIT"):
+			reply("221 Bye")
+			mail <- transcript.String()
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+
+	mail <- transcript.String()
+}
@@ -0,0 +1,169 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package gg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"net"
+	"net/netip"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/highwayhash"
+)
+
+// Default IP truncation of the Pseudonymizer (same as Google Analytics IP anonymization).
+const (
+	DefaultIPv4Bits = 24
+	DefaultIPv6Bits = 48
+)
+
+// Pseudonymizer replaces personal data (IP, User-Agent...) by stable pseudonyms
+// to comply with the GDPR. The pseudonyms are HighwayHash digests keyed by a secret.
+//
+// Contrary to Obfuscate (random key at startup), the key is derived from the secret,
+// thus the replicas sharing the same secret produce the same pseudonyms
+// and the pseudonyms can be correlated over time.
+// The key is rotated every Period (e.g. daily), aligned on UTC midnight
+// (the pseudonyms cannot be correlated across two periods).
+type Pseudonymizer struct {
+	state  atomic.Pointer[pseudonymKey]
+	Period time.Duration // zero = never rotate the key
+	// IPv4Bits is the prefix length kept before hashing, 32 = no truncation.
+	IPv4Bits int
+	// IPv6Bits is the prefix length kept before hashing, 128 = no truncation.
+	IPv6Bits int
+}
+
+type pseudonymKey struct {
+	secret [32]byte
+	key    [32]byte
+	index  int64
+}
+
+// NewPseudonymizer creates a Pseudonymizer from a secret (any length, at least 32 bytes is advised).
+// The key is derived from the secret and rotated every period (zero = never rotate).
+// The IPs are truncated to /24 (IPv4) and /48 (IPv6) before hashing,
+// change IPv4Bits and IPv6Bits to keep more or less bits.
+func NewPseudonymizer(secret []byte, period time.Duration) *Pseudonymizer {
+	if len(secret) == 0 {
+		log.Panic("gg.NewPseudonymizer() requires a secret")
+	}
+
+	p := &Pseudonymizer{
+		state:    atomic.Pointer[pseudonymKey]{},
+		Period:   period,
+		IPv4Bits: DefaultIPv4Bits,
+		IPv6Bits: DefaultIPv6Bits,
+	}
+	p.SetSecret(secret)
+	return p
+}
+
+// SetSecret replaces the secret, for an immediate key rotation.
+func (p *Pseudonymizer) SetSecret(secret []byte) {
+	s := sha256.Sum256(secret)
+	p.state.Store(p.derive(s, p.index(time.Now())))
+}
+
+// Pseudonym returns the keyed hash of str encoded in 11 Base64 characters.
+// Pseudonym returns an empty string if str is empty.
+func (p *Pseudonymizer) Pseudonym(str string) string {
+	if str == "" {
+		return ""
+	}
+	key := p.key()
+	digest := highwayhash.Sum64([]byte(str), key[:])
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], digest)
+	return base64.RawURLEncoding.EncodeToString(b[:])
+}
+
+// IP returns the pseudonym of the truncated IP.
+// The addr may contain a port ("host:port" as http.Request.RemoteAddr), the port is dropped.
+func (p *Pseudonymizer) IP(addr string) string {
+	return p.Pseudonym(p.TruncateIP(addr))
+}
+
+// TruncateIP drops the port (if any) and masks the IP to keep IPv4Bits or IPv6Bits.
+// TruncateIP returns addr unchanged if it is not an IP.
+func (p *Pseudonymizer) TruncateIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return addr
+	}
+	ip = ip.Unmap()
+
+	bits := p.IPv6Bits
+	if ip.Is4() {
+		bits = p.IPv4Bits
+	}
+	if bits <= 0 || bits >= ip.BitLen() {
+		return ip.String()
+	}
+
+	prefix, err := ip.Prefix(bits)
+	if err != nil {
+		return addr
+	}
+	return prefix.String()
+}
+
+// key returns the key of the current period, rotating it if required.
+func (p *Pseudonymizer) key() [32]byte {
+	current := p.state.Load()
+	index := p.index(time.Now())
+	if current.index == index {
+		return current.key
+	}
+
+	next := p.derive(current.secret, index)
+	p.state.CompareAndSwap(current, next) // if false: already rotated by another goroutine
+	return next.key
+}
+
+func (p *Pseudonymizer) index(now time.Time) int64 {
+	if p.Period <= 0 {
+		return 0
+	}
+	return now.UnixNano() / int64(p.Period)
+}
+
+func (*Pseudonymizer) derive(secret [32]byte, index int64) *pseudonymKey {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(index))
+	return &pseudonymKey{
+		secret: secret,
+		key:    highwayhash.Sum(b[:], secret[:]),
+		index:  index,
+	}
+}
+
+// --------------------------------------
+// Read/write Pseudonymizer to/from context
+
+//nolint:gochecknoglobals // pseudonymizerKey is a Context key and need to be global
+var pseudonymizerKey struct{ pseudonymizer byte }
+
+// PutPseudonymizer stores the Pseudonymizer within the context
+// to enable the privacy mode of the downstream handlers.
+func PutPseudonymizer(ctx context.Context, p *Pseudonymizer) context.Context {
+	return context.WithValue(ctx, pseudonymizerKey, p)
+}
+
+// PseudonymizerFromCtx gets the Pseudonymizer from the context, or nil if absent.
+func PseudonymizerFromCtx(ctx context.Context) *Pseudonymizer {
+	p, _ := ctx.Value(pseudonymizerKey).(*Pseudonymizer)
+	return p
+}
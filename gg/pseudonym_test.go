@@ -0,0 +1,71 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package gg_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/teal-finance/garcon/gg"
+)
+
+func TestPseudonymizer_TruncateIP(t *testing.T) {
+	t.Parallel()
+
+	p := gg.NewPseudonymizer([]byte("secret"), 0)
+
+	cases := []struct {
+		addr string
+		want string
+	}{
+		{"192.0.2.123:4567", "192.0.2.0/24"},
+		{"192.0.2.123", "192.0.2.0/24"},
+		{"[2001:db8:1234:5678::1]:443", "2001:db8:1234::/48"},
+		{"::ffff:192.0.2.9", "192.0.2.0/24"},
+		{"not-an-ip", "not-an-ip"},
+	}
+
+	for _, c := range cases {
+		if got := p.TruncateIP(c.addr); got != c.want {
+			t.Errorf("TruncateIP(%q) = %q want %q", c.addr, got, c.want)
+		}
+	}
+}
+
+func TestPseudonymizer_Pseudonym(t *testing.T) {
+	t.Parallel()
+
+	p1 := gg.NewPseudonymizer([]byte("shared-secret"), 24*time.Hour)
+	p2 := gg.NewPseudonymizer([]byte("shared-secret"), 24*time.Hour)
+	p3 := gg.NewPseudonymizer([]byte("other-secret"), 24*time.Hour)
+
+	a := p1.IP("192.0.2.1:1234")
+	if len(a) != 11 {
+		t.Errorf("pseudonym %q should have 11 characters", a)
+	}
+	if b := p2.IP("192.0.2.200:9999"); b != a {
+		t.Errorf("replicas sharing the same secret should produce the same pseudonym: %q != %q", a, b)
+	}
+	if c := p3.IP("192.0.2.1:1234"); c == a {
+		t.Error("different secrets should produce different pseudonyms")
+	}
+	if p1.Pseudonym("") != "" {
+		t.Error("empty string should remain empty")
+	}
+
+	p2.SetSecret([]byte("rotated-secret"))
+	if b := p2.IP("192.0.2.1:1234"); b == a {
+		t.Error("SetSecret should rotate the key")
+	}
+
+	// one key per microsecond
+	p4 := gg.NewPseudonymizer([]byte("shared-secret"), time.Microsecond)
+	before := p4.Pseudonym("User-Agent")
+	time.Sleep(time.Millisecond)
+	if after := p4.Pseudonym("User-Agent"); after == before {
+		t.Error("the key should be rotated every Period")
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package gg
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+)
+
+// RequestIDHeader is the HTTP header conveying the request ID.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLen limits the incoming request ID to prevent log flooding.
+const maxRequestIDLen = 64
+
+// NewRequestID generates a random request ID of 16 Base64 characters (96 bits).
+func NewRequestID() string {
+	return base64.RawURLEncoding.EncodeToString(RandomBytes(12))
+}
+
+// SanitizeRequestID returns the incoming request ID if it is safe to be logged and echoed,
+// otherwise returns an empty string.
+// The accepted characters are [A-Za-z0-9] and "-_.:+/=" (UUID, Base64, ULID...).
+func SanitizeRequestID(id string) string {
+	if len(id) > maxRequestIDLen {
+		return ""
+	}
+
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		switch {
+		case 'a' <= c && c <= 'z',
+			'A' <= c && c <= 'Z',
+			'0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == ':', c == '+', c == '/', c == '=':
+		default:
+			return ""
+		}
+	}
+
+	return id
+}
+
+// --------------------------------------
+// Read/write request ID to/from context
+
+//nolint:gochecknoglobals // requestIDKey is a Context key and need to be global
+var requestIDKey struct{ requestID byte }
+
+// PutRequestID stores the request ID within the context.
+func PutRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestIDFromCtx gets the request ID from the context, or an empty string if absent.
+func RequestIDFromCtx(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// RTRequestID is a RoundTrip middleware setting the X-Request-ID header
+// of the outgoing request from the request ID stored in the request context (if any).
+// RTChain.Then already uses RTRequestID.
+func RTRequestID(next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		id := RequestIDFromCtx(r.Context())
+		if id == "" || r.Header.Get(RequestIDHeader) != "" {
+			return next.RoundTrip(r)
+		}
+
+		r = r.Clone(r.Context()) // RoundTripper must not modify the original request
+		r.Header.Set(RequestIDHeader, id)
+		return next.RoundTrip(r)
+	})
+}
@@ -0,0 +1,364 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package gg
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// rotatedTimeFormat is appended to the rotated file names.
+// This format sorts the rotated files by name in chronological order.
+const rotatedTimeFormat = "20060102-150405.000000"
+
+// RotatingFile is an io.WriteCloser appending to a file (typically an access log file)
+// and rotating it when its size exceeds MaxSize or when it is older than Interval.
+//
+// The rotated file "access.log" is renamed "access.log.20060102-150405.000000"
+// then compressed in background when Encoding is one of SupportedEncoders()
+// (".zst", ".br", ".gz" or ".s2").
+// The rotated files exceeding MaxBackups or older than MaxAge are removed.
+//
+// RotatingFile reopens its file on SIGHUP,
+// allowing an external logrotate to move the file (without copytruncate).
+//
+// The exported fields must be set before the first Write.
+//
+//	rf, err := gg.NewRotatingFile("/var/log/api/access.log")
+//	rf.MaxSize = 100 << 20 // 100 MB
+//	rf.Encoding = gg.ZStdExt
+//	al := garcon.NewAccessLogger(garcon.NewAccessLogHandler(garcon.FormatJSON, rf))
+type RotatingFile struct {
+	file     *os.File
+	opened   time.Time
+	sighup   chan os.Signal
+	closed   bool
+	Path     string
+	Encoding string // one of SupportedEncoders(), empty = no compression
+	// MaxSize in bytes triggering the rotation, zero = no size-based rotation.
+	MaxSize int64
+	// Interval triggering the rotation, zero = no time-based rotation.
+	Interval time.Duration
+	// MaxAge of the rotated files, zero = keep whatever their age.
+	MaxAge time.Duration
+	size   int64
+	// MaxBackups is the maximum number of rotated files, zero = keep all.
+	MaxBackups int
+	Level      int // compression level
+	mu         sync.Mutex
+	bgMu       sync.Mutex // serializes compression and retention
+	wg         sync.WaitGroup
+}
+
+// Default settings of the RotatingFile.
+const (
+	defaultRotateMaxSize    = 100 << 20 // 100 MB
+	defaultRotateMaxBackups = 10
+	defaultRotateLevel      = 3
+)
+
+// NewRotatingFile opens (or creates) the file in append mode.
+// By default, NewRotatingFile rotates every 100 MB, keeps 10 rotated files
+// and does not compress them (see Encoding).
+func NewRotatingFile(path string) (*RotatingFile, error) {
+	rf := &RotatingFile{
+		file:       nil,
+		opened:     time.Time{},
+		sighup:     make(chan os.Signal, 1),
+		closed:     false,
+		Path:       path,
+		Encoding:   "",
+		MaxSize:    defaultRotateMaxSize,
+		Interval:   0,
+		MaxAge:     0,
+		size:       0,
+		MaxBackups: defaultRotateMaxBackups,
+		Level:      defaultRotateLevel,
+		mu:         sync.Mutex{},
+		bgMu:       sync.Mutex{},
+		wg:         sync.WaitGroup{},
+	}
+
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+
+	signal.Notify(rf.sighup, syscall.SIGHUP)
+	go rf.reopenOnSignal()
+
+	return rf, nil
+}
+
+// Write appends b to the file, rotating the file before if required.
+// When a previous rotation (or reopen) could not open the file,
+// Write retries to open it: the logging resumes as soon as the file can be opened.
+func (rf *RotatingFile) Write(b []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.closed {
+		return 0, os.ErrClosed
+	}
+
+	if rf.file == nil {
+		if err := rf.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if rf.mustRotate(int64(len(b))) {
+		if err := rf.rotate(); err != nil {
+			log.Warn("RotatingFile:", err)
+			if rf.file == nil {
+				return 0, err // the next Write retries to open the file
+			}
+			// the file has not been renamed: continue writing into it
+		}
+	}
+
+	n, err := rf.file.Write(b)
+	rf.size += int64(n)
+	return n, err
+}
+
+// Rotate forces the rotation of the file.
+func (rf *RotatingFile) Rotate() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.closed {
+		return os.ErrClosed
+	}
+	return rf.rotate()
+}
+
+// Reopen closes and reopens the file (the file may have been moved by logrotate).
+// When the file cannot be opened, the next Write retries.
+func (rf *RotatingFile) Reopen() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.closed {
+		return os.ErrClosed
+	}
+
+	var err error
+	if rf.file != nil {
+		err = rf.file.Close()
+		rf.file = nil
+	}
+	return errors.Join(err, rf.open())
+}
+
+// Close stops listening SIGHUP, closes the file
+// and waits for the background compression to complete.
+func (rf *RotatingFile) Close() error {
+	signal.Stop(rf.sighup)
+
+	rf.mu.Lock()
+	var err error
+	if !rf.closed {
+		rf.closed = true
+		close(rf.sighup) // stops reopenOnSignal, even if the file is not opened
+		if rf.file != nil {
+			err = rf.file.Close()
+			rf.file = nil
+		}
+	}
+	rf.mu.Unlock()
+
+	rf.wg.Wait()
+	return err
+}
+
+func (rf *RotatingFile) reopenOnSignal() {
+	for range rf.sighup {
+		log.Info("RotatingFile: SIGHUP => reopen " + rf.Path)
+		if err := rf.Reopen(); err != nil {
+			log.Warn("RotatingFile:", err)
+		}
+	}
+}
+
+func (rf *RotatingFile) open() error {
+	err := os.MkdirAll(filepath.Dir(rf.Path), 0o750)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(rf.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return errors.Join(err, file.Close())
+	}
+
+	rf.file = file
+	rf.size = info.Size()
+	rf.opened = time.Now()
+	return nil
+}
+
+func (rf *RotatingFile) mustRotate(n int64) bool {
+	if rf.size == 0 {
+		return false // do not rotate an empty file
+	}
+	if rf.MaxSize > 0 && rf.size+n > rf.MaxSize {
+		return true
+	}
+	return rf.Interval > 0 && time.Since(rf.opened) >= rf.Interval
+}
+
+// rotate renames the current file, opens a new one
+// and compresses/removes the rotated files in background.
+// When the new file cannot be opened, rf.file is nil and the next Write retries.
+func (rf *RotatingFile) rotate() error {
+	if rf.file != nil {
+		if err := rf.file.Close(); err != nil {
+			log.Warn("RotatingFile:", err)
+		}
+		rf.file = nil
+	}
+
+	rotated := rf.rotatedName(time.Now())
+	errRename := os.Rename(rf.Path, rotated)
+	if errRename != nil {
+		rotated = ""
+	}
+
+	if err := rf.open(); err != nil {
+		return errors.Join(errRename, err)
+	}
+
+	rf.wg.Add(1)
+	go rf.compressAndPrune(rotated, rf.Encoding, rf.Level, rf.MaxBackups, rf.MaxAge)
+
+	return errRename
+}
+
+// rotatedName returns a file name not yet used (even if compressed).
+func (rf *RotatingFile) rotatedName(now time.Time) string {
+	for {
+		fn := rf.Path + "." + now.Format(rotatedTimeFormat)
+		matches, _ := filepath.Glob(fn + "*")
+		if len(matches) == 0 {
+			return fn
+		}
+		now = now.Add(time.Microsecond)
+	}
+}
+
+func (rf *RotatingFile) compressAndPrune(rotated, ext string, level, maxBackups int, maxAge time.Duration) {
+	defer rf.wg.Done()
+
+	rf.bgMu.Lock()
+	defer rf.bgMu.Unlock()
+
+	if rotated != "" && ext != "" {
+		if err := compressFile(rotated, ext, level); err != nil {
+			log.Warn("RotatingFile:", err)
+		}
+	}
+
+	rf.prune(maxBackups, maxAge)
+}
+
+// compressFile compresses fn into fn+ext and then removes fn.
+func compressFile(fn, ext string, level int) error {
+	if !isSupportedEncoder(ext) {
+		return errors.New("cannot compress " + fn + ": encoding " + ext + " is not in " + strings.Join(SupportedEncoders(), " "))
+	}
+
+	src, err := os.Open(fn)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil // already removed by the retention policy
+	}
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(fn+ext, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o640)
+	if err != nil {
+		return err
+	}
+
+	enc, err := Compressor(dst, ext, level)
+	if err != nil {
+		return errors.Join(err, dst.Close(), os.Remove(fn+ext))
+	}
+
+	_, err = io.Copy(enc, src)
+	err = errors.Join(err, enc.Close(), dst.Close())
+	if err != nil {
+		return errors.Join(err, os.Remove(fn+ext))
+	}
+
+	return os.Remove(fn)
+}
+
+func isSupportedEncoder(ext string) bool {
+	for _, e := range SupportedEncoders() {
+		if e == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// prune removes the oldest rotated files exceeding maxBackups or older than maxAge.
+func (rf *RotatingFile) prune(maxBackups int, maxAge time.Duration) {
+	rotated := rf.RotatedFiles()
+
+	// most recent first
+	for i, fn := range rotated {
+		remove := maxBackups > 0 && i >= maxBackups
+		if !remove && maxAge > 0 {
+			info, err := os.Stat(fn)
+			remove = (err == nil) && time.Since(info.ModTime()) > maxAge
+		}
+		if remove {
+			if err := os.Remove(fn); err != nil {
+				log.Warn("RotatingFile:", err)
+			}
+		}
+	}
+}
+
+// RotatedFiles returns the rotated files (compressed or not), the most recent first.
+func (rf *RotatingFile) RotatedFiles() []string {
+	matches, err := filepath.Glob(rf.Path + ".*")
+	if err != nil {
+		log.Warn("RotatingFile:", err)
+		return nil
+	}
+
+	rotated := make([]string, 0, len(matches))
+	for _, fn := range matches {
+		suffix := strings.TrimPrefix(fn, rf.Path+".")
+		if len(suffix) < len(rotatedTimeFormat) {
+			continue
+		}
+		if _, err := time.Parse(rotatedTimeFormat, suffix[:len(rotatedTimeFormat)]); err != nil {
+			continue
+		}
+		rotated = append(rotated, fn)
+	}
+
+	sort.Sort(sort.Reverse(sort.StringSlice(rotated)))
+	return rotated
+}
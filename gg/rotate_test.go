@@ -0,0 +1,170 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package gg_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/teal-finance/garcon/gg"
+)
+
+func TestRotatingFile(t *testing.T) {
+	t.Parallel()
+
+	for _, ext := range append(gg.SupportedEncoders(), "") {
+		t.Run("encoding="+ext, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "logs", "access.log")
+			rf, err := gg.NewRotatingFile(path)
+			if err != nil {
+				t.Fatal("NewRotatingFile:", err)
+			}
+			rf.MaxSize = 30
+			rf.MaxBackups = 2
+			rf.Encoding = ext
+
+			line := []byte("0123456789 0123456789\n") // 22 bytes => one line per file
+			for range 5 {
+				if _, err = rf.Write(line); err != nil {
+					t.Fatal("Write:", err)
+				}
+			}
+
+			if err = rf.Close(); err != nil {
+				t.Fatal("Close:", err)
+			}
+
+			current, err := os.ReadFile(path)
+			if err != nil || !bytes.Equal(current, line) {
+				t.Errorf("current file = %q err=%v want %q", current, err, line)
+			}
+
+			rotated := rf.RotatedFiles()
+			if len(rotated) != rf.MaxBackups {
+				t.Fatalf("got %d rotated files %v want %d", len(rotated), rotated, rf.MaxBackups)
+			}
+
+			for _, fn := range rotated {
+				if !strings.HasSuffix(fn, ext) {
+					t.Errorf("rotated file %s should have the extension %q", fn, ext)
+				}
+				if got := gg.Decompress(fn, ext); !bytes.Equal(got, line) {
+					t.Errorf("rotated file %s contains %q want %q", fn, got, line)
+				}
+			}
+		})
+	}
+}
+
+func TestRotatingFile_Reopen(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "access.log")
+	rf, err := gg.NewRotatingFile(path)
+	if err != nil {
+		t.Fatal("NewRotatingFile:", err)
+	}
+	defer rf.Close()
+
+	rf.Write([]byte("before\n"))
+
+	// simulate logrotate moving the file
+	if err = os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err = rf.Reopen(); err != nil {
+		t.Fatal("Reopen:", err)
+	}
+
+	rf.Write([]byte("after\n"))
+
+	if got, _ := os.ReadFile(path); string(got) != "after\n" {
+		t.Errorf("reopened file contains %q want %q", got, "after\n")
+	}
+	if got, _ := os.ReadFile(path + ".1"); string(got) != "before\n" {
+		t.Errorf("moved file contains %q want %q", got, "before\n")
+	}
+}
+
+func TestRotatingFile_RetryOpen(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "logs")
+	path := filepath.Join(dir, "access.log")
+	rf, err := gg.NewRotatingFile(path)
+	if err != nil {
+		t.Fatal("NewRotatingFile:", err)
+	}
+
+	// the directory is replaced by a regular file: the file cannot be reopened
+	if err = os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(dir, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err = rf.Reopen(); err == nil {
+		t.Fatal("Reopen should fail")
+	}
+	if _, err = rf.Write([]byte("lost\n")); err == nil {
+		t.Error("Write should fail while the file cannot be opened")
+	}
+
+	// the next Write opens the file again
+	if err = os.Remove(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = rf.Write([]byte("resumed\n")); err != nil {
+		t.Fatal("Write should reopen the file:", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "resumed\n" {
+		t.Errorf("file contains %q want %q", got, "resumed\n")
+	}
+
+	if err = rf.Close(); err != nil {
+		t.Error("Close:", err)
+	}
+	if _, err = rf.Write([]byte("closed\n")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Write after Close: got %v want %v", err, os.ErrClosed)
+	}
+}
+
+func TestRotatingFile_CloseAfterFailedOpen(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "logs")
+	rf, err := gg.NewRotatingFile(filepath.Join(dir, "access.log"))
+	if err != nil {
+		t.Fatal("NewRotatingFile:", err)
+	}
+
+	if err = os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(dir, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err = rf.Reopen(); err == nil {
+		t.Fatal("Reopen should fail")
+	}
+
+	// Close must stop the SIGHUP goroutine even without opened file
+	if err = rf.Close(); err != nil {
+		t.Error("Close:", err)
+	}
+	if err = rf.Close(); err != nil {
+		t.Error("second Close:", err)
+	}
+	if err = rf.Reopen(); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Reopen after Close: got %v want %v", err, os.ErrClosed)
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/teal-finance/garcon/gg"
+)
+
+// builtinLocales translates the errors registered by Garcon.
+//
+//go:embed locales/*.json
+var builtinLocales embed.FS
+
+// Locales contains the message catalogues, one per language.
+// A catalogue maps a key to the translated message.
+// The key is the code of a registered error (see RegisterError),
+// or any key used with Translate, or the original message (gettext style).
+// The messages of the Default language are the ones written in the code:
+// the Default language does not need a catalogue.
+// Locales must be filled at startup time: Load and Add are not safe for concurrent use.
+type Locales struct {
+	catalogs map[string]map[string]string
+
+	// Default is the language of the messages written in the code,
+	// used when no other language is acceptable.
+	Default string
+}
+
+// NewLocales creates the Locales with the Garcon built-in catalogues (e.g. French).
+// dflt is the language of the messages written in the code (e.g. "en").
+func NewLocales(dflt string) *Locales {
+	l := &Locales{
+		catalogs: make(map[string]map[string]string),
+		Default:  strings.ToLower(dflt),
+	}
+
+	sub, err := fs.Sub(builtinLocales, "locales")
+	if err == nil {
+		err = l.Load(sub)
+	}
+	if err != nil {
+		log.Panic("garcon.NewLocales() cannot load the built-in catalogues:", err)
+	}
+
+	return l
+}
+
+// LoadLocales creates the Locales (see NewLocales) and loads the catalogues from dir.
+func LoadLocales(dir, dflt string) (*Locales, error) {
+	l := NewLocales(dflt)
+	err := l.Load(os.DirFS(dir))
+	return l, err
+}
+
+// Load reads the catalogues "<language>.json" at the root of fsys, such as "fr.json" or "pt-br.json".
+// A catalogue is a JSON object: {"key":"message", ...}.
+// The loaded messages are merged with the existing ones (override the built-in messages).
+func (l *Locales) Load(fsys fs.FS) error {
+	files, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		buf, err := fs.ReadFile(fsys, f)
+		if err != nil {
+			return err
+		}
+
+		var messages map[string]string
+		if err = json.Unmarshal(buf, &messages); err != nil {
+			return fmt.Errorf("locale %s: %w", f, err)
+		}
+
+		l.Add(strings.TrimSuffix(path.Base(f), ".json"), messages)
+	}
+
+	return nil
+}
+
+// Add merges the messages into the catalogue of the language.
+func (l *Locales) Add(lang string, messages map[string]string) {
+	lang = strings.ToLower(lang)
+	c, ok := l.catalogs[lang]
+	if !ok {
+		c = make(map[string]string, len(messages))
+		l.catalogs[lang] = c
+	}
+	for k, v := range messages {
+		c[k] = v
+	}
+}
+
+// Languages returns the Default language and the languages having a catalogue.
+func (l *Locales) Languages() []string {
+	langs := make([]string, 0, len(l.catalogs)+1)
+	for lang := range l.catalogs {
+		if lang != l.Default {
+			langs = append(langs, lang)
+		}
+	}
+	sort.Strings(langs)
+	return append([]string{l.Default}, langs...)
+}
+
+// Negotiate returns the language preferred by the "Accept-Language" header.
+// The language ranges are tried by decreasing quality:
+// the exact language (e.g. "fr-ch"), then its primary language ("fr").
+// Negotiate returns the Default language when no language is available.
+func (l *Locales) Negotiate(acceptLanguage string) string {
+	items := gg.ParseAccept(acceptLanguage)
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Q > items[j].Q })
+
+	for _, it := range items {
+		if it.Q <= 0 {
+			break
+		}
+		if it.Value == "*" {
+			return l.Default
+		}
+		if l.available(it.Value) {
+			return it.Value
+		}
+		if primary, _, ok := strings.Cut(it.Value, "-"); ok && l.available(primary) {
+			return primary
+		}
+	}
+
+	return l.Default
+}
+
+func (l *Locales) available(lang string) bool {
+	_, ok := l.catalogs[lang]
+	return ok || lang == l.Default
+}
+
+// Message returns the message translated in the language,
+// or the fallback when the catalogue has no such key.
+func (l *Locales) Message(lang, key, fallback string) string {
+	if msg, ok := l.catalogs[lang][key]; ok {
+		return msg
+	}
+	return fallback
+}
+
+// localeCtx is stored in the request context by Locales.Middleware.
+type localeCtx struct {
+	locales *Locales
+	lang    string
+}
+
+//nolint:gochecknoglobals // localeKey is a Context key and need to be global
+var localeKey struct{ locale byte }
+
+// Middleware negotiates the language from the "Accept-Language" header
+// and stores it within the request context (see LanguageFromCtx and Translate).
+// Writer.WriteErr translates the error messages of the downstream handlers:
+// register this middleware before the rate limiter and the JWT checker.
+func (l *Locales) Middleware(next http.Handler) http.Handler {
+	log.Info("MiddlewareLocale negotiates the language among", l.Languages())
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lc := &localeCtx{locales: l, lang: l.Negotiate(r.Header.Get("Accept-Language"))}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), localeKey, lc)))
+	})
+}
+
+// WithLocales enables the localization of the error messages (see MiddlewareLocale).
+func WithLocales(l *Locales) Option {
+	return func(g *Garcon) {
+		g.locales = l
+	}
+}
+
+// MiddlewareLocale negotiates the language of the responses (see Locales.Middleware).
+// MiddlewareLocale does nothing when WithLocales is not used.
+func (g *Garcon) MiddlewareLocale() gg.Middleware {
+	if g.locales == nil {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return g.locales.Middleware
+}
+
+// LanguageFromCtx returns the language negotiated by Locales.Middleware,
+// or an empty string when the localization is disabled.
+func LanguageFromCtx(ctx context.Context) string {
+	if lc, ok := ctx.Value(localeKey).(*localeCtx); ok {
+		return lc.lang
+	}
+	return ""
+}
+
+// Translate returns the message of the key in the language negotiated for the request,
+// or the fallback when the localization is disabled or the key is not translated.
+func Translate(r *http.Request, key, fallback string) string {
+	if lc, ok := r.Context().Value(localeKey).(*localeCtx); ok {
+		return lc.locales.Message(lc.lang, key, fallback)
+	}
+	return fallback
+}
+
+// localizedError is a translated error: errors.As still finds the APIError.
+type localizedError struct {
+	err error
+	msg string
+}
+
+func (e *localizedError) Error() string { return e.msg }
+func (e *localizedError) Unwrap() error { return e.err }
+
+// localize translates the error message (first kv) and returns the language of the translation.
+// The APIError are translated from their code,
+// the other messages are translated from the message itself (gettext style).
+func localize(r *http.Request, kv []any) (_ []any, lang string) {
+	if r == nil || len(kv) == 0 {
+		return kv, ""
+	}
+	lc, ok := r.Context().Value(localeKey).(*localeCtx)
+	if !ok || lc.lang == lc.locales.Default {
+		return kv, ""
+	}
+
+	var translated any
+	switch v := kv[0].(type) {
+	case error:
+		var e *APIError
+		if !errors.As(v, &e) {
+			return kv, ""
+		}
+		msg, ok := lc.locales.catalogs[lc.lang][e.Code]
+		if !ok {
+			return kv, ""
+		}
+		translated = &localizedError{err: v, msg: strings.Replace(v.Error(), e.Message, msg, 1)}
+	case string:
+		msg, ok := lc.locales.catalogs[lc.lang][v]
+		if !ok {
+			return kv, ""
+		}
+		translated = msg
+	default:
+		return kv, ""
+	}
+
+	return append([]any{translated}, kv[1:]...), lc.lang
+}
@@ -0,0 +1,171 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/teal-finance/garcon"
+)
+
+func TestLocales_Negotiate(t *testing.T) {
+	t.Parallel()
+
+	l := garcon.NewLocales("en")
+	l.Add("pt-BR", map[string]string{"hello": "olá"})
+
+	cases := []struct {
+		accept string
+		want   string
+	}{
+		{"", "en"},
+		{"fr", "fr"},
+		{"fr-CH, fr;q=0.9, en;q=0.8", "fr"},
+		{"de-DE, de;q=0.9", "en"},
+		{"en;q=0.5, fr;q=0.8", "fr"},
+		{"de, *;q=0.5", "en"},
+		{"fr;q=0, en", "en"},
+		{"pt-br", "pt-br"},
+		{"pt-PT, pt;q=0.9", "en"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.accept, func(t *testing.T) {
+			t.Parallel()
+			if got := l.Negotiate(c.accept); got != c.want {
+				t.Errorf("Negotiate(%q) = %q want %q", c.accept, got, c.want)
+			}
+		})
+	}
+}
+
+func TestWriter_WriteErr_Localized(t *testing.T) {
+	t.Parallel()
+
+	l := garcon.NewLocales("en")
+	l.Add("fr", map[string]string{"Missing parameter": "Paramètre manquant"})
+
+	gw := garcon.NewWriter("https://example.com/doc")
+
+	cases := []struct {
+		name    string
+		lang    string
+		kv      []any
+		message string
+	}{
+		{"api-error-en", "en", []any{garcon.ErrPathTraversal}, "URL contains '..'"},
+		{"api-error-fr", "fr", []any{garcon.ErrPathTraversal}, "L'URL contient '..'"},
+		{"wrapped-fr", "fr", []any{fmt.Errorf("token: %w", garcon.ErrJWTSignature)}, "token: signature du JWT invalide"},
+		{"message-fr", "fr", []any{"Missing parameter", "name", "id"}, "Paramètre manquant"},
+		{"untranslated-fr", "fr", []any{"Unknown message"}, "Unknown message"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := l.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gw.WriteErr(w, r, http.StatusBadRequest, c.kv...)
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/api", nil)
+			r.Header.Set("Accept-Language", c.lang)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			var body map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal("invalid JSON:", err, w.Body.String())
+			}
+			if body["message"] != c.message {
+				t.Errorf("message=%q want %q", body["message"], c.message)
+			}
+
+			wantLang := c.lang
+			if c.message == c.kv[0] || c.lang == "en" {
+				wantLang = ""
+			}
+			if got := w.Header().Get("Content-Language"); got != wantLang {
+				t.Errorf("Content-Language=%q want %q", got, wantLang)
+			}
+			if vary := w.Header().Values("Vary"); len(vary) != 2 || vary[1] != "Accept-Language" {
+				t.Errorf("Vary=%v want [Accept Accept-Language]", vary)
+			}
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if got := garcon.Translate(r, "too_many_requests_advice", "fallback"); got != "fallback" {
+		t.Errorf("without middleware: got %q", got)
+	}
+	if got := garcon.LanguageFromCtx(r.Context()); got != "" {
+		t.Errorf("LanguageFromCtx without middleware: got %q", got)
+	}
+
+	var advice, lang string
+	h := garcon.NewLocales("en").Middleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		advice = garcon.Translate(r, "too_many_requests_advice", "fallback")
+		lang = garcon.LanguageFromCtx(r.Context())
+	}))
+
+	r.Header.Set("Accept-Language", "fr-FR")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	if lang != "fr" {
+		t.Errorf("LanguageFromCtx=%q want fr", lang)
+	}
+	if advice == "fallback" || advice == "" {
+		t.Errorf("Translate=%q want the French advice", advice)
+	}
+}
+
+func TestLoadLocales(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("es.json", `{"path_traversal":"La URL contiene '..'"}`)
+	write("fr.json", `{"path_traversal":"Chemin interdit"}`)
+
+	l, err := garcon.LoadLocales(dir, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := l.Message("es", "path_traversal", ""); got != "La URL contiene '..'" {
+		t.Errorf("es: got %q", got)
+	}
+	if got := l.Message("fr", "path_traversal", ""); got != "Chemin interdit" {
+		t.Errorf("fr override: got %q", got)
+	}
+	if got := l.Message("fr", "maintenance", ""); got == "" {
+		t.Error("fr built-in message lost by the merge")
+	}
+
+	want := []string{"en", "es", "fr"}
+	if got := l.Languages(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Languages=%v want %v", got, want)
+	}
+
+	write("bad.json", `not json`)
+	if _, err := garcon.LoadLocales(dir, "en"); err == nil {
+		t.Error("want an error for the invalid catalogue")
+	}
+}
@@ -8,7 +8,6 @@ package garcon
 import (
 	"context"
 	"encoding/hex"
-	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -38,12 +37,12 @@ const (
 )
 
 var (
-	ErrExpiredToken    = errors.New("expired or invalid access token")
-	ErrJWTSignature    = errors.New("JWT signature mismatch")
-	ErrNoAuthorization = errors.New("provide your JWT within the 'Authorization Bearer' HTTP header")
-	ErrNoBase64JWT     = errors.New("the token claims (second part of the JWT) is not base64-valid")
-	ErrNoBearer        = errors.New("malformed HTTP Authorization, must be Bearer")
-	ErrNoValidJWT      = errors.New("cannot find a valid JWT in either the cookie or the first 'Authorization' HTTP header")
+	ErrExpiredToken    = RegisterError(http.StatusUnauthorized, "expired_token", "expired or invalid access token")
+	ErrJWTSignature    = RegisterError(http.StatusUnauthorized, "jwt_signature", "JWT signature mismatch")
+	ErrNoAuthorization = RegisterError(http.StatusUnauthorized, "no_authorization", "provide your JWT within the 'Authorization Bearer' HTTP header")
+	ErrNoBase64JWT     = RegisterError(http.StatusUnauthorized, "no_base64_jwt", "the token claims (second part of the JWT) is not base64-valid")
+	ErrNoBearer        = RegisterError(http.StatusUnauthorized, "no_bearer", "malformed HTTP Authorization, must be Bearer")
+	ErrNoValidJWT      = RegisterError(http.StatusUnauthorized, "no_valid_jwt", "cannot find a valid JWT in either the cookie or the first 'Authorization' HTTP header")
 )
 
 type Perm struct {
@@ -395,8 +394,10 @@ func PermFromCtx(r *http.Request) Perm {
 }
 
 // PutInCtx stores the permission info within the request context.
+// PutInCtx also records the permission in the current tracing span (if any).
 func (perm Perm) PutInCtx(r *http.Request) *http.Request {
 	parent := r.Context()
+	SpanFromCtx(parent).SetAttribute("garcon.perm", perm.Value)
 	child := context.WithValue(parent, permKey, perm)
 	return r.WithContext(child)
 }
@@ -0,0 +1,225 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/teal-finance/emo"
+	"github.com/teal-finance/garcon/gg"
+)
+
+// LogSampler reduces the number of request log lines at high traffic.
+// The errors (status code outside 2xx) and the slow requests are always logged.
+// The successful requests (2xx) are sampled 1-in-N or by probability.
+type LogSampler struct {
+	// counter of the successful requests (1-in-N sampling).
+	counter atomic.Uint64
+	// SlowThreshold is the duration above which a request is always logged (zero disables).
+	SlowThreshold time.Duration
+	// Probability to log a successful request, used when between 0 and 1 (excluded).
+	Probability float64
+	// Every logs 1-in-Every successful requests (used when Probability is not set).
+	Every uint64
+}
+
+// NewLogSampler logs 1-in-every successful requests.
+// The errors and the requests slower than slowThreshold are always logged.
+func NewLogSampler(every int, slowThreshold time.Duration) *LogSampler {
+	if every < 1 {
+		log.Panic("garcon.NewLogSampler() requires every >= 1, but got", every)
+	}
+	return &LogSampler{
+		counter:       atomic.Uint64{},
+		SlowThreshold: slowThreshold,
+		Probability:   0,
+		Every:         uint64(every),
+	}
+}
+
+// NewProbabilisticLogSampler logs the successful requests with the given probability (0 < p < 1).
+// The errors and the requests slower than slowThreshold are always logged.
+func NewProbabilisticLogSampler(probability float64, slowThreshold time.Duration) *LogSampler {
+	if probability <= 0 || probability >= 1 {
+		log.Panic("garcon.NewProbabilisticLogSampler() requires 0 < probability < 1, but got", probability)
+	}
+	return &LogSampler{
+		counter:       atomic.Uint64{},
+		SlowThreshold: slowThreshold,
+		Probability:   probability,
+		Every:         1,
+	}
+}
+
+// WithLogSampling enables the sampling of the request logs
+// in the middlewares MiddlewareLogRequest, MiddlewareLogDuration and MiddlewareAccessLog.
+func WithLogSampling(s *LogSampler) Option {
+	return func(g *Garcon) {
+		g.logSampler = s
+	}
+}
+
+// Keep returns true when the request must be logged.
+// Keep is safe for concurrent use. A nil LogSampler keeps everything.
+func (s *LogSampler) Keep(statusCode int, d time.Duration) bool {
+	switch {
+	case s == nil:
+		return true
+	case statusCode < http.StatusOK || statusCode >= http.StatusMultipleChoices:
+		return true // always log errors and redirections
+	case s.SlowThreshold > 0 && d >= s.SlowThreshold:
+		return true // always log slow requests
+	case s.Probability > 0 && s.Probability < 1:
+		return rand.Float64() < s.Probability //nolint:gosec // no need of a cryptographic randomness
+	case s.Every > 1:
+		return s.counter.Add(1)%s.Every == 1
+	default:
+		return true
+	}
+}
+
+// middlewareLogRequest logs the incoming request (line) only if kept by the sampler.
+// The line is logged after the response because the status code is required by the sampler.
+func (s *LogSampler) middlewareLogRequest(line func(*http.Request) string) gg.Middleware {
+	log.Infof("MiddlewareLogRequest samples 1-in-%d or %.2f%% successful requests, always logs errors and requests slower than %v",
+		s.Every, 100*s.Probability, s.SlowThreshold)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			record := newResponseRecorder(w)
+			next.ServeHTTP(record, r)
+			if s.Keep(record.StatusCode, record.Duration()) {
+				log.In(line(r))
+			}
+		})
+	}
+}
+
+// middlewareLogDuration is MiddlewareLogDuration(Safe) logging only the requests kept by the sampler.
+func (s *LogSampler) middlewareLogDuration(safe bool) gg.Middleware {
+	log.Infof("MiddlewareLogDuration samples 1-in-%d or %.2f%% successful requests, always logs errors and requests slower than %v",
+		s.Every, 100*s.Probability, s.SlowThreshold)
+
+	line := ipMethodURLDuration
+	if safe {
+		line = ipMethodURLDurationSafe
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			record := newResponseRecorder(w)
+			next.ServeHTTP(record, r)
+			d := record.Duration()
+			if s.Keep(record.StatusCode, d) {
+				log.Out(line(r, StatusCodeStr(record.StatusCode), d, record.Bytes))
+			}
+		})
+	}
+}
+
+// --------------------------------------
+// Deduplication of the repeated log lines
+
+// Settings of the log line deduplication.
+const (
+	dedupThreshold    = 3    // number of identical lines before muting
+	dedupRemind       = 1000 // remind the muted line every 1000 occurrences
+	dedupMaxLines     = 1000 // limit the memory usage, beyond that the lines are not deduplicated
+	dedupTickInterval = time.Second
+	dedupQuietPeriod  = time.Minute
+)
+
+//nolint:gochecknoglobals // shared by the CORS and security middlewares
+var dedup = logDeduper{
+	lines: make(map[string]*dedupLine),
+	mu:    sync.Mutex{},
+	once:  sync.Once{},
+}
+
+// logDeduper collapses the repeated identical log lines
+// (e.g. CORS refusals, whatever the refused origin) using one Muter per line:
+// after dedupThreshold identical lines, the line is muted
+// until the rate of the line goes below one per dedupTickInterval
+// (or after dedupQuietPeriod), then a summary line reports the number of muted lines.
+type logDeduper struct {
+	lines map[string]*dedupLine
+	mu    sync.Mutex
+	once  sync.Once
+}
+
+type dedupLine struct {
+	print    func(args ...any) emo.Event
+	lastSeen time.Time
+	muter    Muter
+}
+
+// log prints the line using the print function unless the line is muted.
+// The line is the deduplication key: it must not contain the attacker-controlled values
+// (URI, Origin...), else every line is new. Such values go into the details,
+// printed after the line only when the line is not muted
+// (the reminders of the muted line do not print the details).
+func (d *logDeduper) log(print func(args ...any) emo.Event, line string, details ...any) {
+	d.once.Do(func() { go d.tick() })
+
+	args := append([]any{line}, details...)
+
+	d.mu.Lock()
+	l, ok := d.lines[line]
+	if !ok {
+		if len(d.lines) >= dedupMaxLines {
+			d.mu.Unlock()
+			print(args...)
+			return
+		}
+		l = &dedupLine{
+			print:    print,
+			lastSeen: time.Time{},
+			muter: Muter{
+				Threshold:       dedupThreshold,
+				NoAlertDuration: dedupQuietPeriod,
+				RemindMuteState: dedupRemind,
+			},
+		}
+		d.lines[line] = l
+	}
+	l.lastSeen = time.Now()
+	ok, dropped := l.muter.Increment()
+	d.mu.Unlock()
+
+	switch {
+	case !ok:
+		return
+	case dropped == 1:
+		print(append(args, "(mute the next identical lines)")...)
+	case dropped > 1:
+		print(line, "(still muted, "+strconv.Itoa(dropped)+" identical lines)")
+	default:
+		print(args...)
+	}
+}
+
+// tick decrements the muters and forgets the lines not seen since one tick.
+func (d *logDeduper) tick() {
+	for range time.NewTicker(dedupTickInterval).C {
+		d.mu.Lock()
+		for line, l := range d.lines {
+			if l.muter.Muted() {
+				if ok, quietTime, dropped := l.muter.Decrement(); ok {
+					l.print(line, "(muted "+strconv.Itoa(dropped)+" identical lines, quiet since "+quietTime.Format(time.TimeOnly)+")")
+					delete(d.lines, line)
+				}
+			} else if time.Since(l.lastSeen) > dedupTickInterval {
+				delete(d.lines, line)
+			}
+		}
+		d.mu.Unlock()
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+//nolint:testpackage // test unexported function
+package garcon
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/teal-finance/emo"
+)
+
+func TestLogSampler_Keep(t *testing.T) {
+	t.Parallel()
+
+	s := NewLogSampler(10, time.Second)
+
+	kept := 0
+	for range 100 {
+		if s.Keep(http.StatusOK, time.Millisecond) {
+			kept++
+		}
+	}
+	if kept != 10 {
+		t.Errorf("1-in-10 sampler kept %d/100 successful requests", kept)
+	}
+
+	for _, code := range []int{http.StatusNotFound, http.StatusInternalServerError, http.StatusFound} {
+		if !s.Keep(code, time.Millisecond) {
+			t.Errorf("status %d should always be logged", code)
+		}
+	}
+
+	for range 10 {
+		if !s.Keep(http.StatusOK, 2*time.Second) {
+			t.Error("slow requests should always be logged")
+		}
+	}
+
+	var nilSampler *LogSampler
+	if !nilSampler.Keep(http.StatusOK, 0) {
+		t.Error("nil sampler should keep everything")
+	}
+
+	p := NewProbabilisticLogSampler(0.5, 0)
+	kept = 0
+	for range 10_000 {
+		if p.Keep(http.StatusNoContent, 0) {
+			kept++
+		}
+	}
+	if kept < 4000 || kept > 6000 {
+		t.Errorf("probabilistic sampler (50%%) kept %d/10000", kept)
+	}
+}
+
+func TestLogDeduper(t *testing.T) {
+	t.Parallel()
+
+	d := logDeduper{lines: make(map[string]*dedupLine), mu: sync.Mutex{}, once: sync.Once{}}
+	d.once.Do(func() {}) // do not start the ticker
+
+	var printed [][]any
+	print := func(args ...any) emo.Event {
+		printed = append(printed, args)
+		return emo.Event{}
+	}
+
+	for range 2500 {
+		d.log(print, "CORS Refuse http://evil")
+	}
+	d.log(print, "another line")
+
+	// 3 first lines + 1 muting notice + 2 reminders (at 1000 and 2000) + another line
+	if len(printed) != 3+1+2+1 {
+		t.Fatalf("got %d printed lines, want 7: %v", len(printed), printed)
+	}
+	if len(printed[3]) != 2 || len(printed[4]) != 2 {
+		t.Errorf("the muting notice and reminders should have a suffix: %v", printed)
+	}
+
+	// quiet period: decrement until un-muted
+	l := d.lines["CORS Refuse http://evil"]
+	l.muter.NoAlertDuration = 0
+	l.muter.Decrement() // first call records the quiet time
+	ok, _, dropped := l.muter.Decrement()
+	if !ok || dropped != 2500-3-1 {
+		t.Errorf("Decrement() = %v, dropped=%d", ok, dropped)
+	}
+}
+
+func TestLogDeduper_Details(t *testing.T) {
+	t.Parallel()
+
+	d := logDeduper{lines: make(map[string]*dedupLine), mu: sync.Mutex{}, once: sync.Once{}}
+	d.once.Do(func() {}) // do not start the ticker
+
+	var printed [][]any
+	print := func(args ...any) emo.Event {
+		printed = append(printed, args)
+		return emo.Event{}
+	}
+
+	// every probe has a different URI: the details do not prevent the collapsing
+	for i := range 1100 {
+		d.log(print, "reject URI:", "/probe/"+strconv.Itoa(i))
+	}
+
+	if len(d.lines) != 1 {
+		t.Errorf("the details should not be part of the key, got %d lines", len(d.lines))
+	}
+	// 3 first lines + 1 muting notice + 1 reminder
+	if len(printed) != 3+1+1 {
+		t.Fatalf("got %d printed lines, want 5: %v", len(printed), printed)
+	}
+	if printed[0][1] != "/probe/0" || printed[3][1] != "/probe/3" || len(printed[3]) != 3 {
+		t.Errorf("the unmuted lines should contain the details: %v", printed[:4])
+	}
+	if len(printed[4]) != 2 {
+		t.Errorf("the reminder should not contain the details: %v", printed[4])
+	}
+}
+
+func TestCORSRefuse_FixedDedupKey(t *testing.T) {
+	t.Parallel()
+
+	const allowed = "https://dedup-key.example.com"
+	allow := oneOrigin(allowed)
+	allowPrefix := multipleOriginPrefixes([]string{allowed + ":"})
+
+	// an attacker sends a different Origin at every request
+	for i := range 50 {
+		origin := "https://evil-" + strconv.Itoa(i) + ".example.com"
+		if allow(origin) || allowPrefix(origin) {
+			t.Fatal("origin should be refused:", origin)
+		}
+	}
+
+	dedup.mu.Lock()
+	defer dedup.mu.Unlock()
+	n := 0
+	for line := range dedup.lines {
+		if strings.Contains(line, "evil-") {
+			t.Errorf("the refused origin must not be part of the dedup key: %q", line)
+		}
+		if strings.Contains(line, allowed) {
+			n++
+		}
+	}
+	if n != 2 {
+		t.Errorf("want one dedup key per allowed origin check, got %d", n)
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	maintenanceMsg = "Service under maintenance. Please retry later."
+	drainingMsg    = `{"message":"Draining, this replica is out of rotation"}`
+)
+
+// Maintenance takes a replica out of rotation without stopping it.
+// The maintenance mode makes the main server answer "503 Service Unavailable"
+// (see MiddlewareMaintenance) while the drain state makes the "/ready" endpoint fail
+// so that the load balancer stops sending new traffic while the current requests finish.
+// Both states are toggled from the admin endpoints of the exporter server (see WithAdmin).
+type Maintenance struct {
+	// Writer writes the JSON error during the maintenance mode.
+	Writer Writer
+
+	// WebServer and Page are optional:
+	// when both are set, the browsers (requests accepting "text/html")
+	// receive the static HTML Page (path relative to WebServer.Dir).
+	WebServer *StaticWebServer
+	Page      string
+
+	// RetryAfter is the duration set in the "Retry-After" response header.
+	// Zero value disables this header.
+	RetryAfter time.Duration
+
+	enabled  atomic.Bool
+	draining atomic.Bool
+}
+
+// NewMaintenance creates a Maintenance.
+// The optional page is the HTML file served to the browsers during the maintenance mode.
+func (g *Garcon) NewMaintenance(retryAfter time.Duration, page ...string) *Maintenance {
+	return NewMaintenance(g.Writer, retryAfter, page...)
+}
+
+// NewMaintenance creates a Maintenance.
+// The optional page is the HTML file served to the browsers during the maintenance mode.
+// The page path is relative to the current directory, use the Maintenance.WebServer field
+// to share the directory of an existing StaticWebServer.
+func NewMaintenance(gw Writer, retryAfter time.Duration, page ...string) *Maintenance {
+	m := &Maintenance{
+		Writer:     gw,
+		WebServer:  nil,
+		Page:       "",
+		RetryAfter: retryAfter,
+		enabled:    atomic.Bool{},
+		draining:   atomic.Bool{},
+	}
+
+	switch len(page) {
+	case 0:
+	case 1:
+		ws := NewStaticWebServer(gw, path.Dir(page[0]))
+		m.WebServer = &ws
+		m.Page = path.Base(page[0])
+	default:
+		log.Panic("garcon.NewMaintenance() accepts up to one page, got", len(page))
+	}
+
+	return m
+}
+
+// Enabled reports whether the maintenance mode is on.
+func (m *Maintenance) Enabled() bool { return m.enabled.Load() }
+
+// Draining reports whether the "/ready" endpoint fails.
+func (m *Maintenance) Draining() bool { return m.draining.Load() }
+
+// Enable switches on/off the maintenance mode.
+func (m *Maintenance) Enable(on bool) {
+	if m.enabled.Swap(on) != on {
+		log.State("Maintenance mode:", on)
+	}
+}
+
+// Drain switches on/off the drain state.
+func (m *Maintenance) Drain(on bool) {
+	if m.draining.Swap(on) != on {
+		log.State("Drain state:", on)
+	}
+}
+
+// MiddlewareMaintenance answers "503 Service Unavailable" while the maintenance mode is on.
+func (g *Garcon) MiddlewareMaintenance(m *Maintenance) func(next http.Handler) http.Handler {
+	return m.Middleware
+}
+
+// Middleware answers "503 Service Unavailable" while the maintenance mode is on.
+// The response also contains the "Retry-After" header (if RetryAfter > 0).
+// The browsers receive the static maintenance page (if any),
+// the other clients receive the JSON error from Writer.WriteErr.
+// The JSON error is also the fallback when the maintenance page is missing.
+func (m *Maintenance) Middleware(next http.Handler) http.Handler {
+	log.Info("MiddlewareMaintenance answers 503 when maintenance mode is on, Retry-After:", m.RetryAfter)
+
+	var absPath string
+	if m.WebServer != nil && m.Page != "" {
+		absPath = path.Join(m.WebServer.Dir, m.Page)
+	}
+
+	retryAfter := ""
+	if m.RetryAfter > 0 {
+		retryAfter = strconv.Itoa(int(m.RetryAfter.Seconds()))
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !m.enabled.Load() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if retryAfter != "" {
+			w.Header().Set("Retry-After", retryAfter)
+		}
+
+		if absPath != "" && strings.Contains(r.Header.Get("Accept"), "text/html") && pageExists(absPath) {
+			w.Header().Set("Cache-Control", "no-store")
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			m.WebServer.sendStatus(w, r, absPath, http.StatusServiceUnavailable)
+			return
+		}
+
+		m.Writer.WriteErr(w, r, http.StatusServiceUnavailable, ErrMaintenance)
+	})
+}
+
+// pageExists returns false (and logs a warning) when the maintenance page is missing.
+func pageExists(absPath string) bool {
+	_, err := os.Stat(absPath)
+	if err != nil {
+		log.Warn("Maintenance: missing page, answer the JSON error:", err)
+		return false
+	}
+	return true
+}
+
+// WithAdmin enables the admin endpoints "/maintenance" and "/drain" on the exporter server.
+// The admin requests must provide the token in the "Authorization: Bearer" header:
+//
+//	curl            -H "Authorization: Bearer $TOKEN" http://localhost:9093/maintenance # get state
+//	curl -X POST    -H "Authorization: Bearer $TOKEN" http://localhost:9093/maintenance # enable
+//	curl -X DELETE  -H "Authorization: Bearer $TOKEN" http://localhost:9093/maintenance # disable
+//
+// The same for "/drain": when enabled, the "/ready" endpoint fails.
+func WithAdmin(token string, m *Maintenance) ProbeOption {
+	if token == "" {
+		log.Panic("garcon.WithAdmin() requires a non-empty token")
+	}
+	if m == nil {
+		log.Panic("garcon.WithAdmin() requires a non-nil Maintenance")
+	}
+
+	return func(h *exporterHandler) {
+		h.adminToken = []byte(token)
+		h.maintenance = m
+	}
+}
+
+// handleAdmin processes the admin endpoints "/maintenance" and "/drain".
+func (h *exporterHandler) handleAdmin(w http.ResponseWriter, r *http.Request, name string, state func() bool, toggle func(bool)) {
+	if !h.authorized(r) {
+		log.Security("Exporter: unauthorized", ipMethodURLSafe(r))
+		WriteErr(w, r, http.StatusUnauthorized, "Provide the admin token within the 'Authorization Bearer' HTTP header")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost, http.MethodPut:
+		toggle(true)
+	case http.MethodDelete:
+		toggle(false)
+	default:
+		w.Header().Set("Allow", "GET, POST, PUT, DELETE")
+		WriteErr(w, r, http.StatusMethodNotAllowed, "Only GET, POST, PUT and DELETE are allowed")
+		return
+	}
+
+	WriteOK(w, name, state())
+}
+
+func (h *exporterHandler) authorized(r *http.Request) bool {
+	auth := r.Header.Get("Authorization")
+	n := len(authScheme)
+	if len(auth) <= n || auth[:n] != authScheme {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(auth[n:]), h.adminToken) == 1
+}
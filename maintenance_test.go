@@ -0,0 +1,109 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+//nolint:testpackage // test unexported function
+package garcon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMaintenance(t *testing.T) {
+	t.Parallel()
+
+	m := NewMaintenance(NewWriter("/doc"), time.Minute)
+	exporter := newExporterHandler(WithAdmin("secret", m))
+	main := m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { WriteOK(w) }))
+
+	steps := []struct {
+		name   string
+		h      http.Handler
+		method string
+		path   string
+		token  string
+		want   int
+	}{
+		{"main-ok", main, http.MethodGet, "/", "", http.StatusOK},
+		{"ready-ok", exporter, http.MethodGet, "/ready", "", http.StatusOK},
+		{"no-token", exporter, http.MethodPost, "/maintenance", "", http.StatusUnauthorized},
+		{"bad-token", exporter, http.MethodPost, "/maintenance", "wrong", http.StatusUnauthorized},
+		{"enable", exporter, http.MethodPost, "/maintenance", "secret", http.StatusOK},
+		{"main-503", main, http.MethodGet, "/", "", http.StatusServiceUnavailable},
+		{"drain", exporter, http.MethodPut, "/drain", "secret", http.StatusOK},
+		{"ready-503", exporter, http.MethodGet, "/ready", "", http.StatusServiceUnavailable},
+		{"health-ok", exporter, http.MethodGet, "/health", "", http.StatusOK},
+		{"undrain", exporter, http.MethodDelete, "/drain", "secret", http.StatusOK},
+		{"disable", exporter, http.MethodDelete, "/maintenance", "secret", http.StatusOK},
+		{"bad-method", exporter, http.MethodPatch, "/maintenance", "secret", http.StatusMethodNotAllowed},
+		{"main-ok-again", main, http.MethodGet, "/", "", http.StatusOK},
+		{"ready-ok-again", exporter, http.MethodGet, "/ready", "", http.StatusOK},
+	}
+
+	for _, s := range steps {
+		r := httptest.NewRequest(s.method, s.path, http.NoBody)
+		if s.token != "" {
+			r.Header.Set("Authorization", "Bearer "+s.token)
+		}
+		w := httptest.NewRecorder()
+
+		s.h.ServeHTTP(w, r)
+
+		if w.Code != s.want {
+			t.Errorf("%s: got status %d, want %d body=%s", s.name, w.Code, s.want, w.Body.String())
+		}
+		if s.want == http.StatusServiceUnavailable && s.h != exporter {
+			if got := w.Header().Get("Retry-After"); got != "60" {
+				t.Errorf("%s: got Retry-After=%q, want 60", s.name, got)
+			}
+		}
+	}
+}
+
+func TestMaintenance_Page(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	page := filepath.Join(dir, "maintenance.html")
+	if err := os.WriteFile(page, []byte("<h1>Maintenance</h1>"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		page     string
+		accept   string
+		wantType string
+	}{
+		{"page", page, "text/html", "text/html; charset=utf-8"},
+		{"api-client", page, "application/json", "application/json"},
+		{"missing-page", filepath.Join(dir, "missing.html"), "text/html", "application/json"},
+	}
+
+	for _, c := range cases {
+		m := NewMaintenance(NewWriter("/doc"), time.Minute, c.page)
+		m.Enable(true)
+		h := m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { WriteOK(w) }))
+
+		r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		r.Header.Set("Accept", c.accept)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s: got status %d, want 503 body=%s", c.name, w.Code, w.Body.String())
+		}
+		if got := w.Header().Get("Retry-After"); got != "60" {
+			t.Errorf("%s: got Retry-After=%q, want 60", c.name, got)
+		}
+		if got := w.Header().Get("Content-Type"); got != c.wantType {
+			t.Errorf("%s: got Content-Type=%q, want %q", c.name, got, c.wantType)
+		}
+	}
+}
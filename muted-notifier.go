@@ -0,0 +1,254 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/teal-finance/garcon/gg"
+)
+
+// Default settings of the MutedNotifier.
+const (
+	defaultMutedThreshold     = 5
+	defaultMutedRemind        = 1000
+	defaultMutedCheckInterval = time.Minute
+	maxSimilarityKeyLen       = 100
+)
+
+// MutedNotifier wraps a gg.Notifier to protect the chat from alert storms.
+// The messages are grouped by Key (default: SimilarityKey).
+// When a group receives more than Threshold messages,
+// the group is muted using the Muter hysteresis.
+// When the storm ends, MutedNotifier sends a digest
+// with the number of dropped messages and the last one.
+type MutedNotifier struct {
+	notifier gg.Notifier
+	groups   map[string]*mutedGroup
+
+	// Key groups the similar messages. Default is SimilarityKey.
+	Key func(msg string) string
+
+	// QuietDuration is the NoAlertDuration of the Muter (see Muter).
+	QuietDuration time.Duration
+
+	// CheckInterval is the period to detect the end of the storms.
+	// Zero (or negative) means the default period (one minute).
+	CheckInterval time.Duration
+
+	// Threshold is the number of similar messages before muting (see Muter).
+	Threshold int
+
+	// RemindMuteState reminds the storm every N dropped messages (see Muter).
+	RemindMuteState int
+
+	// silentUntil is the end of the silence requested by Silence.
+	silentUntil time.Time
+	silenced    int    // messages dropped during the silence
+	lastSilent  string // last message dropped during the silence
+
+	mu   sync.Mutex
+	once sync.Once
+}
+
+type mutedGroup struct {
+	lastSeen time.Time
+	last     string // last message, reported in the digest
+	muter    *Muter
+}
+
+// NewMutedNotifier wraps the notifier to mute the bursts of similar messages.
+// threshold is the number of similar messages before muting,
+// quietDuration is the time without similar messages to end the storm.
+func NewMutedNotifier(notifier gg.Notifier, threshold int, quietDuration time.Duration) *MutedNotifier {
+	if notifier == nil {
+		log.Panic("garcon.NewMutedNotifier() requires a Notifier")
+	}
+	if threshold < 1 {
+		threshold = defaultMutedThreshold
+	}
+
+	return &MutedNotifier{
+		notifier:        notifier,
+		groups:          make(map[string]*mutedGroup),
+		Key:             SimilarityKey,
+		QuietDuration:   quietDuration,
+		CheckInterval:   defaultMutedCheckInterval,
+		Threshold:       threshold,
+		RemindMuteState: defaultMutedRemind,
+		silentUntil:     time.Time{},
+		silenced:        0,
+		lastSilent:      "",
+		mu:              sync.Mutex{},
+		once:            sync.Once{},
+	}
+}
+
+// Notify forwards the message unless its group is muted.
+// The first muted message is forwarded with a notice.
+func (mn *MutedNotifier) Notify(msg string) error {
+	notice, ok := mn.admit(msg)
+	if !ok {
+		return nil
+	}
+	if notice != "" {
+		msg += "\n\n" + notice
+	}
+	return mn.notifier.Notify(msg)
+}
+
+// NotifyMessage is Notify for a structured message, rendered by the wrapped notifier
+// with its own markup (see gg.NotifyMessage). The Key groups the plain text renderings.
+func (mn *MutedNotifier) NotifyMessage(m gg.Message) error {
+	notice, ok := mn.admit(m.Render(gg.FormatPlain))
+	if !ok {
+		return nil
+	}
+	if notice != "" {
+		m.Body = strings.TrimSpace(m.Body + "\n\n" + notice)
+	}
+	return gg.NotifyMessage(mn.notifier, m)
+}
+
+// admit returns false when the group of the message is muted,
+// and the notice to append to the first muted messages.
+func (mn *MutedNotifier) admit(msg string) (notice string, ok bool) {
+	mn.once.Do(func() { go mn.checkPeriodically(mn.CheckInterval) })
+
+	key := mn.Key(msg)
+
+	mn.mu.Lock()
+	if time.Now().Before(mn.silentUntil) {
+		mn.silenced++
+		mn.lastSilent = msg
+		mn.mu.Unlock()
+		return "", false
+	}
+	g, found := mn.groups[key]
+	if !found {
+		g = &mutedGroup{
+			lastSeen: time.Time{},
+			last:     "",
+			muter: &Muter{
+				Threshold:       mn.Threshold,
+				NoAlertDuration: mn.QuietDuration,
+				RemindMuteState: mn.RemindMuteState,
+			},
+		}
+		mn.groups[key] = g
+	}
+	g.lastSeen = time.Now()
+	g.last = msg
+	ok, dropped := g.muter.Increment()
+	mn.mu.Unlock()
+
+	switch {
+	case !ok:
+		return "", false
+	case dropped == 1:
+		return "(alert storm: the similar messages are muted)", true
+	case dropped > 1:
+		return "(alert storm continues: " + strconv.Itoa(dropped) + " similar messages muted)", true
+	default:
+		return "", true
+	}
+}
+
+// Silence drops all the messages during the duration d (e.g. bot command "/mute 1h").
+// The next Check after the silence sends a digest of the dropped messages.
+// Silence(0) ends the current silence.
+func (mn *MutedNotifier) Silence(d time.Duration) {
+	mn.mu.Lock()
+	mn.silentUntil = time.Now().Add(d)
+	mn.mu.Unlock()
+}
+
+// Check decrements the Muter of the groups without any message since the previous check,
+// and sends a digest for each ended storm.
+// Check is called every CheckInterval.
+func (mn *MutedNotifier) Check() error {
+	var digests []string
+
+	mn.mu.Lock()
+	if mn.silenced > 0 && !time.Now().Before(mn.silentUntil) {
+		digests = append(digests, "Silence ended: "+strconv.Itoa(mn.silenced)+" messages muted"+
+			"\n\n"+"Last message:\n"+mn.lastSilent)
+		mn.silenced = 0
+		mn.lastSilent = ""
+	}
+	for key, g := range mn.groups {
+		if time.Since(g.lastSeen) < mn.CheckInterval {
+			continue // storm in progress
+		}
+		if !g.muter.Muted() {
+			delete(mn.groups, key) // forget the quiet groups
+			continue
+		}
+		if ok, quietTime, dropped := g.muter.Decrement(); ok {
+			digests = append(digests, digest(dropped, quietTime, g.last))
+			delete(mn.groups, key)
+		}
+	}
+	mn.mu.Unlock()
+
+	var err error
+	for _, d := range digests {
+		err = errors.Join(err, mn.notifier.Notify(d))
+	}
+	return err
+}
+
+func (mn *MutedNotifier) checkPeriodically(interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultMutedCheckInterval // time.NewTicker panics
+	}
+	for range time.NewTicker(interval).C {
+		if err := mn.Check(); err != nil {
+			log.Warn("MutedNotifier:", err)
+		}
+	}
+}
+
+func digest(dropped int, quietTime time.Time, last string) string {
+	msg := "Alert storm ended: " + strconv.Itoa(dropped) + " similar messages muted"
+	if !quietTime.IsZero() {
+		msg += ", quiet since " + quietTime.Format(time.DateTime)
+	}
+	return msg + "\n\n" + "Last message:\n" + last
+}
+
+// SimilarityKey groups the messages differing only by numbers (timestamps, IDs, durations...).
+// SimilarityKey keeps the first line, replaces the digit sequences by "#"
+// and truncates to 100 bytes.
+func SimilarityKey(msg string) string {
+	if i := strings.IndexByte(msg, '\n'); i >= 0 {
+		msg = msg[:i]
+	}
+
+	var sb strings.Builder
+	sb.Grow(min(len(msg), maxSimilarityKeyLen))
+	digits := false
+	for _, r := range msg {
+		if unicode.IsDigit(r) {
+			if !digits {
+				sb.WriteByte('#')
+			}
+			digits = true
+			continue
+		}
+		digits = false
+		sb.WriteRune(r)
+		if sb.Len() >= maxSimilarityKeyLen {
+			break
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/teal-finance/garcon"
+	"github.com/teal-finance/garcon/gg"
+)
+
+type recordNotifier struct{ messages []string }
+
+func (n *recordNotifier) Notify(msg string) error {
+	n.messages = append(n.messages, msg)
+	return nil
+}
+
+func TestMutedNotifier(t *testing.T) {
+	t.Parallel()
+
+	rec := &recordNotifier{}
+	mn := garcon.NewMutedNotifier(rec, 3, time.Hour)
+	mn.CheckInterval = time.Hour
+
+	for i := range 100 {
+		if err := mn.Notify("DB timeout after " + strconv.Itoa(i) + " ms\nretry"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := mn.Notify("disk full"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 3 messages + 1 muting notice + "disk full"
+	if len(rec.messages) != 5 {
+		t.Fatalf("got %d messages, want 5: %q", len(rec.messages), rec.messages)
+	}
+	if !strings.Contains(rec.messages[3], "muted") || rec.messages[4] != "disk full" {
+		t.Errorf("unexpected messages %q", rec.messages)
+	}
+
+	// the storm is still in progress
+	if err := mn.Check(); err != nil || len(rec.messages) != 5 {
+		t.Fatalf("Check() should not send a digest during the storm: err=%v %q", err, rec.messages)
+	}
+
+	// the storm ends: the Muter counter decreases at every check
+	mn.CheckInterval = 0
+	for range 100 {
+		if err := mn.Check(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(rec.messages) != 6 {
+		t.Fatalf("got %d messages, want 6 (with the digest): %q", len(rec.messages), rec.messages)
+	}
+	d := rec.messages[5]
+	if !strings.Contains(d, "96 similar messages muted") || !strings.Contains(d, "DB timeout after 99 ms") {
+		t.Errorf("unexpected digest %q", d)
+	}
+}
+
+func TestMutedNotifier_ZeroCheckInterval(t *testing.T) {
+	t.Parallel()
+
+	mn := garcon.NewMutedNotifier(&recordNotifier{}, 1, time.Hour)
+	mn.CheckInterval = 0 // the background check uses the default period
+
+	if err := mn.Notify("hello"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond) // let the background goroutine start
+}
+
+// messageRecorder records the structured messages.
+type messageRecorder struct {
+	recordNotifier
+	structured []gg.Message
+}
+
+func (n *messageRecorder) NotifyMessage(m gg.Message) error {
+	n.structured = append(n.structured, m)
+	return nil
+}
+
+func TestMutedNotifier_NotifyMessage(t *testing.T) {
+	t.Parallel()
+
+	rec := &messageRecorder{}
+	mn := garcon.NewMutedNotifier(rec, 1, time.Hour)
+	mn.CheckInterval = time.Hour
+
+	for i := range 3 {
+		if err := mn.NotifyMessage(gg.Message{Title: "DB timeout after " + strconv.Itoa(i) + " ms"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 1 message + 1 muting notice, forwarded as structured messages
+	if len(rec.structured) != 2 || len(rec.messages) != 0 {
+		t.Fatalf("structured=%+v plain=%q", rec.structured, rec.messages)
+	}
+	if !strings.Contains(rec.structured[1].Body, "muted") {
+		t.Errorf("missing notice in %+v", rec.structured[1])
+	}
+}
+
+func TestSimilarityKey(t *testing.T) {
+	t.Parallel()
+
+	a := garcon.SimilarityKey("GET /api/v1/items/123 took 4501ms\ndetails")
+	b := garcon.SimilarityKey("GET /api/v1/items/9 took 12ms")
+	if a != b || a != "GET /api/v#/items/# took #ms" {
+		t.Errorf("SimilarityKey: %q != %q", a, b)
+	}
+}
@@ -6,6 +6,7 @@
 package garcon
 
 import (
+	"sync"
 	"time"
 )
 
@@ -15,6 +16,7 @@ import (
 // to return to normal situation.
 // Muter uses the Hysteresis principle: https://wikiless.org/wiki/Hysteresis
 // Similar wording: quieter, stopper, limiter, reducer, inhibitor, mouth-closer.
+// Muter is safe for concurrent use. See also MuterSet for multiple kinds of alerts.
 type Muter struct {
 	// Threshold is the level enabling the muted state.
 	Threshold int
@@ -40,15 +42,22 @@ type Muter struct {
 
 	// dropped is the number of Increment() calls after state became muted.
 	dropped int
+
+	mu sync.Mutex
 }
 
 // Increment increments the internal counter and returns false when in muted state.
 // Every RemindMuteState calls, Increment also returns the number of times Increment has been called.
 func (m *Muter) Increment() (ok bool, dropped int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.counter++
 
 	if m.muted {
 		m.dropped++
+		// the storm continues: the quiet period restarts at the next Decrement()
+		m.quietTime = time.Time{}
 		if (m.RemindMuteState == 0) || (m.dropped%m.RemindMuteState) > 0 {
 			return false, -1
 		}
@@ -71,6 +80,9 @@ func (m *Muter) Increment() (ok bool, dropped int) {
 // Decrement decrements the internal counter and switches to un-muted state
 // when counter reaches zero or after NoAlertDuration.
 func (m *Muter) Decrement() (ok bool, _ time.Time, dropped int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	if !m.muted {
 		return false, time.Time{}, 0 // already un-muted, do nothing
 	}
@@ -94,3 +106,10 @@ func (m *Muter) Decrement() (ok bool, _ time.Time, dropped int) {
 
 	return true, m.quietTime, m.dropped
 }
+
+// Muted returns the current state.
+func (m *Muter) Muted() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.muted
+}
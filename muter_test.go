@@ -0,0 +1,42 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/teal-finance/garcon"
+)
+
+func TestMuter_IncrementRestartsQuietPeriod(t *testing.T) {
+	t.Parallel()
+
+	m := garcon.Muter{Threshold: 1, NoAlertDuration: 50 * time.Millisecond, RemindMuteState: 0}
+	for range 5 {
+		m.Increment()
+	}
+
+	if ok, _, _ := m.Decrement(); ok {
+		t.Fatal("un-muted by the first Decrement")
+	}
+	time.Sleep(60 * time.Millisecond)
+
+	// the storm continues: the quiet period since the first Decrement is broken
+	m.Increment()
+	if ok, _, _ := m.Decrement(); ok || !m.Muted() {
+		t.Fatal("un-muted while the storm continues")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	ok, quietTime, dropped := m.Decrement()
+	if !ok || m.Muted() {
+		t.Fatal("still muted after NoAlertDuration without Increment")
+	}
+	if dropped != 4 || time.Since(quietTime) < 50*time.Millisecond {
+		t.Errorf("dropped=%d quietTime=%v", dropped, quietTime)
+	}
+}
@@ -0,0 +1,287 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// MuterSet limits the alerting verbosity per kind of alert (e.g. "db-timeout", "5xx").
+// Contrary to Muter, MuterSet does not require Increment/Decrement calls:
+// MuterSet counts the alerts within a sliding time Window.
+// A kind becomes muted when more than Threshold alerts occur within the Window,
+// and becomes un-muted when the alert rate goes below the half of Threshold (hysteresis).
+// Every SummaryInterval, MuterSet calls Summary for each kind having suppressed alerts.
+// MuterSet is safe for concurrent use.
+type MuterSet struct {
+	kinds map[string]*muterWindow
+
+	// Summary is called with the number of suppressed alerts since the previous summary.
+	// The default Summary logs "N similar alerts suppressed".
+	Summary func(kind string, suppressed int)
+
+	// Window is the duration of the sliding window counting the alerts.
+	Window time.Duration
+
+	// SummaryInterval is the period between two summaries (default = Window).
+	SummaryInterval time.Duration
+
+	// Threshold is the number of alerts within Window enabling the muted state.
+	Threshold int
+
+	// silentUntil is the end of the silence requested by Silence.
+	silentUntil time.Time
+
+	mu   sync.Mutex
+	once sync.Once
+}
+
+// muterWindow approximates the sliding window count
+// by weighting the count of the previous fixed window.
+type muterWindow struct {
+	start        time.Time // start of the current fixed window
+	previous     int       // count of the previous fixed window
+	current      int       // count of the current fixed window
+	suppressed   int       // suppressed alerts since the last summary
+	totalDropped uint64    // suppressed alerts since the kind is tracked (metrics)
+	muted        bool
+}
+
+// MuterStats is a snapshot of the MuterSet state for one kind of alert.
+type MuterStats struct {
+	Kind         string
+	Rate         float64 // alerts within the sliding window
+	Suppressed   int     // since the last summary
+	TotalDropped uint64  // since the kind is tracked (restarts from zero after eviction)
+	Muted        bool
+}
+
+// NewMuterSet creates a MuterSet muting a kind of alert
+// when more than threshold alerts occur within the sliding window.
+func NewMuterSet(threshold int, window time.Duration) *MuterSet {
+	if threshold < 1 || window <= 0 {
+		log.Panicf("garcon.NewMuterSet() requires threshold>0 and window>0, but got threshold=%d window=%v", threshold, window)
+	}
+
+	return &MuterSet{
+		kinds:           make(map[string]*muterWindow),
+		Summary:         logSuppressed,
+		Window:          window,
+		SummaryInterval: window,
+		Threshold:       threshold,
+		silentUntil:     time.Time{},
+		mu:              sync.Mutex{},
+		once:            sync.Once{},
+	}
+}
+
+// NewMuterSet creates a MuterSet and exports its metrics to Prometheus.
+// The name distinguishes the metrics of several MuterSets.
+func (g *Garcon) NewMuterSet(name string, threshold int, window time.Duration) *MuterSet {
+	s := NewMuterSet(threshold, window)
+	prometheus.MustRegister(s.Collector(g.ServerName, name))
+	return s
+}
+
+func logSuppressed(kind string, suppressed int) {
+	log.Warning(strconv.Itoa(suppressed) + " similar alerts suppressed: " + kind)
+}
+
+// Allow records an alert and returns false when this kind of alert is muted.
+func (s *MuterSet) Allow(kind string) bool {
+	s.once.Do(func() { go s.summarizePeriodically() })
+
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.kinds[kind]
+	if !ok {
+		w = &muterWindow{start: now}
+		s.kinds[kind] = w
+	}
+
+	w.slide(now, s.Window)
+	w.current++
+
+	rate := w.rate(now, s.Window)
+	switch {
+	case !w.muted && rate > float64(s.Threshold):
+		w.muted = true
+	case w.muted && rate < float64(s.Threshold)/2:
+		w.muted = false
+	}
+
+	if w.muted || now.Before(s.silentUntil) {
+		w.suppressed++
+		w.totalDropped++
+		return false
+	}
+	return true
+}
+
+// Silence mutes all kinds of alerts during the duration d (e.g. bot command "/mute 1h").
+// The suppressed alerts are reported by the next summaries.
+// Silence(0) ends the current silence.
+func (s *MuterSet) Silence(d time.Duration) {
+	s.mu.Lock()
+	s.silentUntil = time.Now().Add(d)
+	s.mu.Unlock()
+}
+
+// Muted returns true when this kind of alert is currently muted.
+func (s *MuterSet) Muted(kind string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.kinds[kind]
+	if !ok {
+		return false
+	}
+	s.refresh(w, time.Now())
+	return w.muted
+}
+
+// Stats returns the state of all kinds of alerts, sorted by kind.
+func (s *MuterSet) Stats() []MuterStats {
+	now := time.Now()
+
+	s.mu.Lock()
+	stats := make([]MuterStats, 0, len(s.kinds))
+	for kind, w := range s.kinds {
+		s.refresh(w, now)
+		stats = append(stats, MuterStats{
+			Kind:         kind,
+			Rate:         w.rate(now, s.Window),
+			Suppressed:   w.suppressed,
+			TotalDropped: w.totalDropped,
+			Muted:        w.muted,
+		})
+	}
+	s.mu.Unlock()
+
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Kind < stats[j].Kind })
+	return stats
+}
+
+// Summarize calls Summary for each kind having suppressed alerts
+// and evicts the idle kinds (no alert within the sliding window):
+// a storm of unique kinds does not grow the MuterSet forever.
+// The TotalDropped counter of an evicted kind restarts from zero.
+func (s *MuterSet) Summarize() {
+	type summary struct {
+		kind       string
+		suppressed int
+	}
+	var summaries []summary
+
+	now := time.Now()
+
+	s.mu.Lock()
+	for kind, w := range s.kinds {
+		s.refresh(w, now)
+		if w.suppressed > 0 {
+			summaries = append(summaries, summary{kind, w.suppressed})
+			w.suppressed = 0
+		}
+		if !w.muted && w.previous == 0 && w.current == 0 {
+			delete(s.kinds, kind)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, sum := range summaries {
+		s.Summary(sum.kind, sum.suppressed)
+	}
+}
+
+func (s *MuterSet) summarizePeriodically() {
+	for range time.NewTicker(s.SummaryInterval).C {
+		s.Summarize()
+	}
+}
+
+// refresh slides the window and un-mutes when the alert rate has decreased.
+func (s *MuterSet) refresh(w *muterWindow, now time.Time) {
+	w.slide(now, s.Window)
+	if w.muted && w.rate(now, s.Window) < float64(s.Threshold)/2 {
+		w.muted = false
+	}
+}
+
+// slide moves the fixed windows when the current one is over.
+func (w *muterWindow) slide(now time.Time, window time.Duration) {
+	elapsed := now.Sub(w.start)
+	switch {
+	case elapsed < window:
+		return
+	case elapsed < 2*window:
+		w.previous = w.current
+		w.start = w.start.Add(window)
+	default: // no alert during the last window
+		w.previous = 0
+		w.start = now
+	}
+	w.current = 0
+}
+
+// rate estimates the number of alerts within the sliding window ending now.
+func (w *muterWindow) rate(now time.Time, window time.Duration) float64 {
+	weight := 1 - float64(now.Sub(w.start))/float64(window)
+	if weight < 0 {
+		weight = 0
+	}
+	return float64(w.previous)*weight + float64(w.current)
+}
+
+// --------------------------------------
+// Prometheus metrics
+
+type muterSetCollector struct {
+	set     *MuterSet
+	muted   *prometheus.Desc
+	dropped *prometheus.Desc
+	rate    *prometheus.Desc
+}
+
+// Collector returns a prometheus.Collector exporting the muted state,
+// the dropped alerts and the alert rate per kind.
+func (s *MuterSet) Collector(namespace ServerName, name string) prometheus.Collector {
+	labels := prometheus.Labels{"set": name}
+	return &muterSetCollector{
+		set: s,
+		muted: prometheus.NewDesc(prometheus.BuildFQName(string(namespace), "muter", "muted"),
+			"1 when the kind of alert is muted.", []string{"kind"}, labels),
+		dropped: prometheus.NewDesc(prometheus.BuildFQName(string(namespace), "muter", "dropped_total"),
+			"Number of suppressed alerts.", []string{"kind"}, labels),
+		rate: prometheus.NewDesc(prometheus.BuildFQName(string(namespace), "muter", "window_alerts"),
+			"Number of alerts within the sliding window.", []string{"kind"}, labels),
+	}
+}
+
+func (c *muterSetCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.muted
+	ch <- c.dropped
+	ch <- c.rate
+}
+
+func (c *muterSetCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, st := range c.set.Stats() {
+		muted := 0.0
+		if st.Muted {
+			muted = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.muted, prometheus.GaugeValue, muted, st.Kind)
+		ch <- prometheus.MustNewConstMetric(c.dropped, prometheus.CounterValue, float64(st.TotalDropped), st.Kind)
+		ch <- prometheus.MustNewConstMetric(c.rate, prometheus.GaugeValue, st.Rate, st.Kind)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon_test
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/teal-finance/garcon"
+)
+
+func TestMuterSet(t *testing.T) {
+	t.Parallel()
+
+	s := garcon.NewMuterSet(3, time.Hour)
+
+	var summaries []string
+	s.Summary = func(kind string, suppressed int) {
+		for range suppressed {
+			summaries = append(summaries, kind)
+		}
+	}
+
+	// concurrent alerts of the same kind
+	var wg sync.WaitGroup
+	allowed := make(chan bool, 10)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			allowed <- s.Allow("db-timeout")
+		}()
+	}
+	wg.Wait()
+	close(allowed)
+
+	n := 0
+	for ok := range allowed {
+		if ok {
+			n++
+		}
+	}
+	if n != 3 {
+		t.Errorf("allowed %d alerts, want 3", n)
+	}
+
+	if !s.Allow("5xx") {
+		t.Error("another kind of alert should not be muted")
+	}
+	if !s.Muted("db-timeout") || s.Muted("5xx") {
+		t.Error("only db-timeout should be muted")
+	}
+
+	stats := s.Stats()
+	if len(stats) != 2 || stats[1].Kind != "db-timeout" || stats[1].TotalDropped != 7 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	if c := testutil.CollectAndCount(s.Collector("test", "unit")); c != 3*2 {
+		t.Errorf("collected %d metrics, want 6", c)
+	}
+
+	s.Summarize()
+	if len(summaries) != 7 {
+		t.Errorf("summaries reported %d suppressed alerts, want 7", len(summaries))
+	}
+
+	s.Summarize()
+	if len(summaries) != 7 {
+		t.Error("the suppressed alerts should be reported only once")
+	}
+}
+
+func TestMuterSet_EvictIdleKinds(t *testing.T) {
+	t.Parallel()
+
+	const window = 20 * time.Millisecond
+	s := garcon.NewMuterSet(1, window)
+	s.Summary = func(string, int) {}
+
+	// storm of unique kinds, all having suppressed alerts
+	for i := range 100 {
+		kind := "kind-" + strconv.Itoa(i)
+		s.Allow(kind)
+		s.Allow(kind)
+		s.Allow(kind)
+	}
+	if n := len(s.Stats()); n != 100 {
+		t.Fatalf("tracking %d kinds, want 100", n)
+	}
+
+	time.Sleep(2*window + 10*time.Millisecond) // the kinds become idle
+	s.Summarize()
+
+	if stats := s.Stats(); len(stats) != 0 {
+		t.Errorf("the idle kinds should be evicted after the summary, still %d: %+v", len(stats), stats[0])
+	}
+}
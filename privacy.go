@@ -0,0 +1,98 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/teal-finance/garcon/gg"
+)
+
+// WithPrivacy enables the privacy mode: the logging middlewares, the fingerprint,
+// FingerprintMD and the rate limiter replace the IPs and the identifying headers
+// by pseudonyms. The key is derived from the secret and rotated every period (e.g. 24h).
+// All the replicas should share the same secret to produce the same pseudonyms.
+// See gg.NewPseudonymizer to change the IP truncation (/24 and /48 by default).
+func WithPrivacy(secret []byte, period time.Duration) Option {
+	p := gg.NewPseudonymizer(secret, period)
+	return WithPseudonymizer(p)
+}
+
+// WithPseudonymizer is similar to WithPrivacy using a custom Pseudonymizer.
+func WithPseudonymizer(p *gg.Pseudonymizer) Option {
+	return func(g *Garcon) {
+		g.privacy = p
+	}
+}
+
+// Privacy returns the Pseudonymizer, or nil when the privacy mode is disabled.
+func (g *Garcon) Privacy() *gg.Pseudonymizer { return g.privacy }
+
+// MiddlewarePrivacy stores the Pseudonymizer within the request context
+// to enable the privacy mode of the downstream handlers.
+// The Garcon logging and rate limiter middlewares already do it,
+// MiddlewarePrivacy is useful for the other handlers (e.g. WebForm).
+func (g *Garcon) MiddlewarePrivacy() gg.Middleware {
+	return MiddlewarePrivacy(g.privacy)
+}
+
+// MiddlewarePrivacy stores the Pseudonymizer within the request context.
+// MiddlewarePrivacy does nothing when p is nil.
+func MiddlewarePrivacy(p *gg.Pseudonymizer) gg.Middleware {
+	return func(next http.Handler) http.Handler {
+		if p == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if gg.PseudonymizerFromCtx(r.Context()) == nil {
+				r = r.WithContext(gg.PutPseudonymizer(r.Context(), p))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// withPrivacy wraps the middleware m to run it in privacy mode (if enabled).
+func (g *Garcon) withPrivacy(m gg.Middleware) gg.Middleware {
+	if g.privacy == nil {
+		return m
+	}
+	privacy := MiddlewarePrivacy(g.privacy)
+	return func(next http.Handler) http.Handler {
+		return privacy(m(next))
+	}
+}
+
+// remoteAddr returns the pseudonym of the requester IP in privacy mode,
+// else the RemoteAddr (IP:port).
+func remoteAddr(r *http.Request) string {
+	if p := gg.PseudonymizerFromCtx(r.Context()); p != nil {
+		return p.IP(r.RemoteAddr)
+	}
+	return r.RemoteAddr
+}
+
+// identifyingHeader returns true for the headers replaced by a pseudonym in privacy mode.
+func identifyingHeader(header string) bool {
+	switch header {
+	case "User-Agent", "Referer", "Authorization", "Cookie", "Via", "Forwarded", "X-Forwarded-For", "X-Real-Ip":
+		return true
+	}
+	return false
+}
+
+// safeHeader is gg.SafeHeader but returns a pseudonym
+// for the identifying headers in privacy mode.
+func safeHeader(r *http.Request, header string) string {
+	v := gg.SafeHeader(r, header)
+	if identifyingHeader(header) {
+		if p := gg.PseudonymizerFromCtx(r.Context()); p != nil {
+			return p.Pseudonym(v)
+		}
+	}
+	return v
+}
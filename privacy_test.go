@@ -0,0 +1,80 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/teal-finance/garcon"
+	"github.com/teal-finance/garcon/gg"
+)
+
+func TestMiddlewarePrivacy(t *testing.T) {
+	t.Parallel()
+
+	p := gg.NewPseudonymizer([]byte("secret"), 24*time.Hour)
+
+	var buf bytes.Buffer
+	al := garcon.NewAccessLogger(garcon.NewAccessLogHandler(garcon.FormatJSON, &buf), "ip", "user-agent", "accept")
+	h := gg.NewChain(garcon.MiddlewarePrivacy(p), al.Middleware).Then(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	r.RemoteAddr = "192.0.2.1:1234"
+	r.Header.Set("User-Agent", "curl/8")
+	r.Header.Set("Accept", "text/plain")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	line := buf.String()
+	for _, personal := range []string{"192.0.2", "curl/8"} {
+		if strings.Contains(line, personal) {
+			t.Errorf("privacy mode should not log %q: %s", personal, line)
+		}
+	}
+	for _, want := range []string{`"ip":"` + p.IP(r.RemoteAddr) + `"`, `"User-Agent":"` + p.Pseudonym("curl/8") + `"`, `"Accept":"text/plain"`} {
+		if !strings.Contains(line, want) {
+			t.Errorf("missing %s in %s", want, line)
+		}
+	}
+}
+
+func TestMiddlewareRateLimiter_Privacy(t *testing.T) {
+	t.Parallel()
+
+	p := gg.NewPseudonymizer([]byte("secret"), 24*time.Hour)
+	rl := garcon.NewRateLimiter("", 1, 1, false) // burst=1 then 1 request per minute
+	h := gg.NewChain(garcon.MiddlewarePrivacy(p), rl.MiddlewareRateLimiter).Then(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+
+	// two clients in the same /24: the second one must not be throttled by the first one
+	for _, addr := range []string{"192.0.2.1:1234", "192.0.2.2:1234"} {
+		r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		r.RemoteAddr = addr
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: status=%d want 200 (own bucket)", addr, w.Code)
+		}
+	}
+
+	// the same client is throttled (Wait fails immediately: the next token is beyond the deadline)
+	ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+	defer cancel()
+	r := httptest.NewRequestWithContext(ctx, http.MethodGet, "/", http.NoBody)
+	r.RemoteAddr = "192.0.2.1:5678"
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("status=%d want 429", w.Code)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/teal-finance/garcon/gg"
+)
+
+// Media types negotiated by Writer.WriteErr.
+const (
+	MediaTypeJSON    = "application/json"
+	MediaTypeProblem = "application/problem+json"
+)
+
+//nolint:gochecknoglobals // problemKey is a Context key and need to be global
+var problemKey struct{ problem byte }
+
+// MiddlewareProblemDetails makes the Problem Details format (RFC 9457)
+// the default format of the error responses written by Writer.WriteErr.
+// The clients can still request the legacy format with "Accept: application/json".
+func MiddlewareProblemDetails(next http.Handler) http.Handler {
+	log.Info("MiddlewareProblemDetails: error responses in application/problem+json by default")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), problemKey, true)))
+	})
+}
+
+// wantsProblem negotiates the format of the error response from the "Accept" header:
+// the legacy JSON or the Problem Details (RFC 9457).
+// When both are equally acceptable, the default is the legacy format,
+// or the Problem Details when enabled by MiddlewareProblemDetails.
+func wantsProblem(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+
+	offers := []string{MediaTypeJSON, MediaTypeProblem}
+	if on, _ := r.Context().Value(problemKey).(bool); on {
+		offers[0], offers[1] = offers[1], offers[0]
+	}
+
+	return gg.Negotiate(r.Header.Get("Accept"), offers...) == MediaTypeProblem
+}
+
+// writeProblem writes the error response in the Problem Details format (RFC 9457):
+//
+//	{"type":     docURL (or "about:blank"),
+//	 "title":    status text,
+//	 "status":   status code,
+//	 "detail":   the message (first kv, or the first two kv concatenated as in the legacy format),
+//	 "instance": request URI,
+//	 ...         the other key-values, the request ID and the trace ID as extension members}
+func (gw Writer) writeProblem(w http.ResponseWriter, r *http.Request, statusCode int, kv ...any) {
+	buf := make([]byte, 0, 1024)
+
+	buf = append(buf, []byte(`{"type":`)...)
+	if gw == "" {
+		buf = append(buf, []byte(`"about:blank"`)...)
+	} else {
+		buf = strconv.AppendQuote(buf, string(gw))
+	}
+
+	buf = append(buf, []byte(",\n"+`"title":`)...)
+	buf = strconv.AppendQuote(buf, http.StatusText(statusCode))
+
+	buf = append(buf, []byte(",\n"+`"status":`)...)
+	buf = strconv.AppendInt(buf, int64(statusCode), 10)
+
+	switch len(kv) {
+	case 0:
+	case 2:
+		buf = append(buf, []byte(",\n"+`"detail":`)...)
+		buf = strconv.AppendQuoteToGraphic(buf, fmt.Sprintf("%v%v", kv[0], kv[1]))
+	default:
+		buf = append(buf, []byte(",\n"+`"detail":`)...)
+		buf = strconv.AppendQuoteToGraphic(buf, fmt.Sprint(kv[0]))
+		if len(kv) > 1 {
+			buf = appendKeyValues(buf, true, kv[1:])
+		}
+	}
+
+	if r != nil {
+		buf = append(buf, []byte(",\n"+`"instance":`)...)
+		buf = strconv.AppendQuote(buf, r.URL.RequestURI())
+		buf = appendIDs(buf, r)
+	}
+
+	buf = append(buf, '}')
+
+	w.Header().Set("Content-Type", MediaTypeProblem)
+	w.WriteHeader(statusCode)
+	w.Write(buf)
+}
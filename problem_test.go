@@ -0,0 +1,110 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/teal-finance/garcon"
+)
+
+func TestWriter_WriteErr_ProblemDetails(t *testing.T) {
+	t.Parallel()
+
+	gw := garcon.NewWriter("https://example.com/doc")
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gw.WriteErr(w, r, http.StatusTooManyRequests, "Too Many Requests", "retry_after", 5)
+	})
+
+	cases := []struct {
+		name        string
+		accept      string
+		contentType string
+		middleware  bool
+	}{
+		{"legacy-default", "", "application/json", false},
+		{"legacy-any", "*/*", "application/json", false},
+		{"problem-requested", "application/problem+json", "application/problem+json", false},
+		{"problem-default", "", "application/problem+json", true},
+		{"problem-default-any", "*/*", "application/problem+json", true},
+		{"legacy-requested", "application/json", "application/json", true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			var h http.Handler = handler
+			if c.middleware {
+				h = garcon.MiddlewareProblemDetails(handler)
+			}
+
+			r := httptest.NewRequest(http.MethodGet, "/api/prices?limit=9", nil)
+			r.Header.Set("Accept", c.accept)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if got := w.Header().Get("Content-Type"); got != c.contentType {
+				t.Fatalf("Content-Type=%q want %q", got, c.contentType)
+			}
+			if w.Header().Get("Vary") != "Accept" {
+				t.Errorf("missing Vary: Accept")
+			}
+
+			var body map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid JSON %s: %v", w.Body.String(), err)
+			}
+
+			want := map[string]any{
+				"message":     "Too Many Requests",
+				"retry_after": 5.0,
+				"path":        "/api/prices",
+				"query":       "limit=9",
+				"doc":         "https://example.com/doc",
+			}
+			if c.contentType == "application/problem+json" {
+				want = map[string]any{
+					"type":        "https://example.com/doc",
+					"title":       "Too Many Requests",
+					"status":      429.0,
+					"detail":      "Too Many Requests",
+					"instance":    "/api/prices?limit=9",
+					"retry_after": 5.0,
+				}
+			}
+
+			if len(body) != len(want) {
+				t.Errorf("body=%v want %v", body, want)
+			}
+			for k, v := range want {
+				if body[k] != v {
+					t.Errorf("%s=%v want %v", k, body[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteErr_ProblemDetails_AboutBlank(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet, "/x", nil)
+	r.Header.Set("Accept", "application/problem+json")
+	w := httptest.NewRecorder()
+	garcon.WriteErr(w, r, http.StatusNotFound, "not found")
+
+	var body map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body["type"] != "about:blank" || body["detail"] != "not found" || body["title"] != "Not Found" {
+		t.Errorf("body=%v", body)
+	}
+}
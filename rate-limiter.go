@@ -31,6 +31,12 @@ type visitor struct {
 }
 
 func (g *Garcon) MiddlewareRateLimiter(settings ...int) gg.Middleware {
+	reqLimiter := g.newRateLimiter("MiddlewareRateLimiter", settings)
+	return g.withPrivacy(reqLimiter.MiddlewareRateLimiter)
+}
+
+// newRateLimiter parses the optional settings: maxReqBurst and maxReqPerMinute.
+func (g *Garcon) newRateLimiter(caller string, settings []int) ReqLimiter {
 	var maxReqBurst, maxReqPerMinute int
 
 	switch len(settings) {
@@ -44,11 +50,10 @@ func (g *Garcon) MiddlewareRateLimiter(settings ...int) gg.Middleware {
 		maxReqBurst = settings[0]
 		maxReqPerMinute = settings[1]
 	default:
-		log.Panic("garcon.MiddlewareRateLimiter() accepts up to two arguments, got", len(settings))
+		log.Panicf("garcon.%s() accepts up to two arguments, got %d", caller, len(settings))
 	}
 
-	reqLimiter := NewRateLimiter(g.Writer, maxReqBurst, maxReqPerMinute, g.devMode)
-	return reqLimiter.MiddlewareRateLimiter
+	return NewRateLimiter(g.Writer, maxReqBurst, maxReqPerMinute, g.devMode)
 }
 
 func NewRateLimiter(gw Writer, maxReqBurst, maxReqPerMinute int, devMode bool) ReqLimiter {
@@ -77,20 +82,26 @@ func (rl *ReqLimiter) MiddlewareRateLimiter(next http.Handler) http.Handler {
 		ip, _, err := net.SplitHostPort(r.RemoteAddr)
 		if err != nil {
 			rl.gw.WriteErr(w, r, http.StatusInternalServerError,
-				"Cannot split remote_addr=host:port", "remote_addr", r.RemoteAddr)
-			log.Out("500", r.RemoteAddr, r.Method, r.RequestURI, "Split host:port ERROR:", err)
+				"Cannot split remote_addr=host:port", "remote_addr", remoteAddr(r))
+			log.Out("500", remoteAddr(r), r.Method, r.RequestURI, "Split host:port ERROR:", err)
 			return
 		}
 
+		// privacy mode: the visitors are identified by the pseudonym of their full IP
+		// (the truncated IP would share the same bucket between the clients of a /24)
+		if p := gg.PseudonymizerFromCtx(r.Context()); p != nil {
+			ip = p.Pseudonym(ip)
+		}
+
 		limiter := rl.getVisitor(ip)
 
 		if err := limiter.Wait(r.Context()); err != nil {
 			if r.Context().Err() == nil {
-				rl.gw.WriteErr(w, r, http.StatusTooManyRequests, "Too Many Requests",
-					"advice", "Please contact the team support is this is annoying")
-				log.Out("429", r.RemoteAddr, r.Method, r.RequestURI, "ERROR:", err)
+				rl.gw.WriteErr(w, r, http.StatusTooManyRequests, ErrTooManyRequests,
+					"advice", Translate(r, "too_many_requests_advice", "Please contact the team support is this is annoying"))
+				log.Out("429", remoteAddr(r), r.Method, r.RequestURI, "ERROR:", err)
 			} else {
-				log.In("-->", r.RemoteAddr, r.Method, r.RequestURI, "ERROR:", err)
+				log.In("-->", remoteAddr(r), r.Method, r.RequestURI, "ERROR:", err)
 			}
 			return
 		}
@@ -99,6 +110,11 @@ func (rl *ReqLimiter) MiddlewareRateLimiter(next http.Handler) http.Handler {
 	})
 }
 
+// NewLimiter returns a new rate.Limiter having the same settings (burst and rate).
+func (rl *ReqLimiter) NewLimiter() *rate.Limiter {
+	return rate.NewLimiter(rl.initLimiter.Limit(), rl.initLimiter.Burst())
+}
+
 func (rl *ReqLimiter) removeOldVisitors() {
 	for ; true; <-time.NewTicker(1 * time.Minute).C {
 		rl.mu.Lock()
@@ -120,7 +136,7 @@ func (rl *ReqLimiter) getVisitor(ip string) *rate.Limiter {
 	v, ok := rl.visitors[ip]
 	if !ok {
 		v = &visitor{
-			limiter:  rl.initLimiter,
+			limiter:  rl.NewLimiter(), // one bucket per visitor
 			lastSeen: time.Time{},
 		}
 		rl.visitors[ip] = v
@@ -0,0 +1,51 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/teal-finance/garcon"
+)
+
+func TestMiddlewareRateLimiter_BucketPerVisitor(t *testing.T) {
+	t.Parallel()
+
+	rl := garcon.NewRateLimiter("", 2, 1, false) // burst=2 then 1 request per minute
+	h := rl.MiddlewareRateLimiter(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+
+	serve := func(addr string) int {
+		// Wait fails immediately when the next token is beyond the deadline
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "/", http.NoBody)
+		r.RemoteAddr = addr
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// the first visitor consumes its whole burst
+	for range 2 {
+		if code := serve("198.51.100.1:1234"); code != http.StatusOK {
+			t.Fatalf("first visitor: status=%d want 200", code)
+		}
+	}
+	if code := serve("198.51.100.1:1234"); code != http.StatusTooManyRequests {
+		t.Errorf("first visitor beyond its burst: status=%d want 429", code)
+	}
+
+	// another visitor has its own bucket
+	for range 2 {
+		if code := serve("203.0.113.7:1234"); code != http.StatusOK {
+			t.Errorf("second visitor: status=%d want 200 (not throttled by the first visitor)", code)
+		}
+	}
+}
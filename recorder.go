@@ -0,0 +1,139 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// responseRecorder wraps the http.ResponseWriter to capture
+// the status code, the number of written bytes and the time-to-first-byte.
+// responseRecorder is shared by all the logging and metrics middlewares.
+//
+// responseRecorder preserves the optional interfaces
+// http.Flusher, http.Hijacker, http.Pusher and io.ReaderFrom
+// (required by Server-Sent Events, WebSocket and sendfile)
+// and implements Unwrap() for http.ResponseController.
+type responseRecorder struct {
+	http.ResponseWriter
+	start       time.Time
+	firstByte   time.Time
+	StatusCode  int
+	Bytes       int64
+	wroteHeader bool
+}
+
+func newResponseRecorder(w http.ResponseWriter) *responseRecorder {
+	return &responseRecorder{
+		ResponseWriter: w,
+		start:          time.Now(),
+		firstByte:      time.Time{},
+		StatusCode:     http.StatusOK,
+		Bytes:          0,
+		wroteHeader:    false,
+	}
+}
+
+// Duration returns the elapsed time since the recorder creation.
+func (r *responseRecorder) Duration() time.Duration {
+	return time.Since(r.start)
+}
+
+// TTFB returns the time-to-first-byte: the duration until the response header is sent.
+// TTFB returns zero if nothing has been sent.
+func (r *responseRecorder) TTFB() time.Duration {
+	if r.firstByte.IsZero() {
+		return 0
+	}
+	return r.firstByte.Sub(r.start)
+}
+
+// WriteHeader records the first final status code only:
+// the informational status codes (1xx) may be sent several times.
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		if r.firstByte.IsZero() {
+			r.firstByte = time.Now()
+		}
+		r.StatusCode = status
+		r.wroteHeader = (status >= http.StatusOK) || (status == http.StatusSwitchingProtocols)
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.Bytes += int64(n)
+	return n, err
+}
+
+// ReadFrom preserves the io.ReaderFrom optimization (sendfile) of the underlying writer.
+func (r *responseRecorder) ReadFrom(src io.Reader) (int64, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	var n int64
+	var err error
+	if rf, ok := r.ResponseWriter.(io.ReaderFrom); ok {
+		n, err = rf.ReadFrom(src)
+	} else {
+		n, err = io.Copy(writerOnly{r.ResponseWriter}, src)
+	}
+	r.Bytes += n
+	return n, err
+}
+
+// Flush sends any buffered data to the client (Server-Sent Events...).
+func (r *responseRecorder) Flush() {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack lets the handler take over the connection (WebSocket...).
+func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+
+	conn, rw, err := hj.Hijack()
+	if err == nil && !r.wroteHeader {
+		r.firstByte = time.Now()
+		r.StatusCode = http.StatusSwitchingProtocols
+		r.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+// Push initiates an HTTP/2 server push.
+func (r *responseRecorder) Push(target string, opts *http.PushOptions) error {
+	if p, ok := r.ResponseWriter.(http.Pusher); ok {
+		return p.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
+// Unwrap is used by http.ResponseController.
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// writerOnly hides the ReadFrom method to prevent io.Copy infinite recursion.
+type writerOnly struct {
+	io.Writer
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+//nolint:testpackage // test unexported function
+package garcon
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResponseRecorder(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		handler   func(w http.ResponseWriter)
+		wantCode  int
+		wantBytes int64
+	}{
+		{"implicit-200", func(w http.ResponseWriter) { w.Write([]byte("hello")) }, http.StatusOK, 5},
+		{"explicit-404", func(w http.ResponseWriter) { w.WriteHeader(http.StatusNotFound) }, http.StatusNotFound, 0},
+		{"first-status-wins", func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusCreated)
+			w.WriteHeader(http.StatusInternalServerError)
+		}, http.StatusCreated, 0},
+		{"informational-then-final", func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusEarlyHints)
+			w.WriteHeader(http.StatusAccepted)
+		}, http.StatusAccepted, 0},
+		{"read-from", func(w http.ResponseWriter) {
+			if _, err := w.(io.ReaderFrom).ReadFrom(strings.NewReader("0123456789")); err != nil {
+				panic(err)
+			}
+		}, http.StatusOK, 10},
+		{"flush", func(w http.ResponseWriter) {
+			w.Write([]byte("data: 1\n\n"))
+			if err := http.NewResponseController(w).Flush(); err != nil {
+				panic(err)
+			}
+		}, http.StatusOK, 9},
+		{"hijack-not-supported", func(w http.ResponseWriter) {
+			_, _, err := http.NewResponseController(w).Hijack()
+			if !errors.Is(err, http.ErrNotSupported) {
+				panic(err)
+			}
+			w.WriteHeader(http.StatusTeapot)
+		}, http.StatusTeapot, 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			record := newResponseRecorder(w)
+			c.handler(record)
+
+			if record.StatusCode != c.wantCode {
+				t.Errorf("StatusCode=%d want %d", record.StatusCode, c.wantCode)
+			}
+			if record.Bytes != c.wantBytes {
+				t.Errorf("Bytes=%d want %d", record.Bytes, c.wantBytes)
+			}
+			if int64(w.Body.Len()) != c.wantBytes {
+				t.Errorf("body has %d bytes, want %d", w.Body.Len(), c.wantBytes)
+			}
+			if c.name == "flush" && !w.Flushed {
+				t.Error("Flush() not propagated to the underlying ResponseWriter")
+			}
+			if record.TTFB() < 0 || record.TTFB() > record.Duration() {
+				t.Errorf("TTFB=%v out of range [0..%v]", record.TTFB(), record.Duration())
+			}
+		})
+	}
+}
+
+func TestMiddlewareLogDurationSafe_Status(t *testing.T) {
+	t.Parallel()
+
+	var got *responseRecorder
+	h := MiddlewareLogDurationSafe(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		got, _ = w.(*responseRecorder)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	if got == nil {
+		t.Fatal("MiddlewareLogDurationSafe does not pass its recorder to the next handler")
+	}
+	if got.StatusCode != http.StatusNotFound {
+		t.Errorf("StatusCode=%d want 404", got.StatusCode)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon
+
+import (
+	"net/http"
+
+	"github.com/teal-finance/garcon/gg"
+)
+
+// MiddlewareRequestID accepts the incoming "X-Request-ID" header (if safe) or generates a new one,
+// stores the request ID in the request context and echoes it in the response header.
+func (g *Garcon) MiddlewareRequestID() gg.Middleware {
+	return MiddlewareRequestID
+}
+
+// MiddlewareRequestID accepts the incoming "X-Request-ID" header (if safe)
+// or generates a new request ID. Then, the request ID is stored in the request context
+// and echoed in the response header.
+//
+// The request ID is then automatically included in:
+//   - the Writer.WriteErr body,
+//   - the log lines of the logging middlewares (placed after this one in the chain),
+//   - the outgoing requests made through gg.RTChain (using the request context).
+func MiddlewareRequestID(next http.Handler) http.Handler {
+	log.Info("MiddlewareRequestID sets the header " + gg.RequestIDHeader)
+
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			id := gg.SanitizeRequestID(r.Header.Get(gg.RequestIDHeader))
+			if id == "" {
+				id = gg.NewRequestID()
+			}
+
+			w.Header().Set(gg.RequestIDHeader, id)
+			ctx := gg.PutRequestID(r.Context(), id)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+}
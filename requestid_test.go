@@ -0,0 +1,70 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/teal-finance/garcon"
+	"github.com/teal-finance/garcon/gg"
+)
+
+func TestMiddlewareRequestID(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		incoming string
+		keep     bool // incoming ID echoed as is
+	}{
+		{"generated", "", false},
+		{"accepted", "abc-123_XYZ", true},
+		{"unsafe", "abc\r\ninjected: 1", false},
+		{"quote", `abc"def`, false},
+		{"too-long", strings.Repeat("a", 1000), false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			var ctxID string
+			h := garcon.MiddlewareRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctxID = gg.RequestIDFromCtx(r.Context())
+				garcon.WriteErr(w, r, http.StatusTeapot, "short and stout")
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			if c.incoming != "" {
+				r.Header.Set(gg.RequestIDHeader, c.incoming)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			id := w.Header().Get(gg.RequestIDHeader)
+			switch {
+			case id == "":
+				t.Fatal("response misses the header", gg.RequestIDHeader)
+			case c.keep && id != c.incoming:
+				t.Errorf("request ID = %q, want the incoming %q", id, c.incoming)
+			case !c.keep && id == c.incoming:
+				t.Errorf("unsafe incoming request ID %q must be replaced", c.incoming)
+			case gg.SanitizeRequestID(id) != id:
+				t.Errorf("request ID %q is not safe", id)
+			}
+
+			if ctxID != id {
+				t.Errorf("context request ID = %q, want %q", ctxID, id)
+			}
+			if !strings.Contains(w.Body.String(), `"request_id":"`+id+`"`) {
+				t.Error("WriteErr body misses the request_id:", w.Body.String())
+			}
+		})
+	}
+}
@@ -27,10 +27,8 @@ func MiddlewareRejectUnprintableURI(next http.Handler) http.Handler {
 	return http.HandlerFunc(
 		func(w http.ResponseWriter, r *http.Request) {
 			if i := gg.Printable(r.RequestURI); i >= 0 {
-				WriteErr(w, r, http.StatusBadRequest,
-					"Invalid URI with non-printable symbol",
-					"position", i)
-				log.Warn("reject non-printable URI or with <CR> or <LF>:", gg.Sanitize(r.RequestURI))
+				WriteErr(w, r, http.StatusBadRequest, ErrUnprintableURI, "position", i)
+				dedup.log(log.Warn, "reject non-printable URI or with <CR> or <LF>:", gg.Sanitize(r.RequestURI))
 				return
 			}
 
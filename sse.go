@@ -0,0 +1,438 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// MediaTypeEventStream is the media type of the Server-Sent Events.
+const MediaTypeEventStream = "text/event-stream"
+
+// ErrSSETopic is answered when the request has no valid topic.
+var ErrSSETopic = RegisterError(http.StatusBadRequest, "sse_topic",
+	"Provide one or several known topics: ?topic=xxx&topic=yyy")
+
+// SSEEvent is an event published by the SSEHub, already encoded in the SSE format.
+type SSEEvent struct {
+	Topic string
+	ID    uint64 // sequence number of the SSEHub, sent as the SSE "id" field
+	frame []byte
+}
+
+// SSEHub broadcasts the Server-Sent Events to the clients subscribed to the topics.
+//
+// The clients subscribe to one or several topics with the query parameter "topic":
+//
+//	const es = new EventSource("/events?topic=btc&topic=eth")
+//
+// The events get an increasing ID: on reconnection, the browser sends the "Last-Event-ID" header
+// and the SSEHub replays the missed events still present in the ring buffer of each topic.
+// A comment is sent every Heartbeat to keep the connection alive through the proxies.
+// A client is slow when its queue is full: the SSEHub drops its events
+// (or disconnects it when DisconnectSlow is true, the browser reconnects and replays the missed events).
+//
+// The SSEHub extends the write deadline at each write: the events can be streamed longer
+// than the WriteTimeout of the server (see Garcon.Server).
+type SSEHub struct {
+	// Writer writes the JSON errors (invalid topic...).
+	Writer Writer
+
+	// Heartbeat is the period of the keep-alive comments.
+	Heartbeat time.Duration
+
+	// ReplaySize is the number of events kept per topic for the "Last-Event-ID" replay.
+	// Zero disables the replay. ReplaySize must be set before the first Publish.
+	ReplaySize int
+
+	// QueueSize is the number of events waiting to be sent to one client.
+	QueueSize int
+
+	// DisconnectSlow disconnects the clients having a full queue
+	// instead of dropping their events.
+	DisconnectSlow bool
+
+	topics map[string]*sseTopic
+
+	clients   atomic.Int64
+	published atomic.Uint64
+	dropped   atomic.Uint64
+	kicked    atomic.Uint64
+
+	seq uint64
+	mu  sync.Mutex
+}
+
+type sseTopic struct {
+	vet     http.Handler // TokenChecker.Vet middleware (nil = public topic), built by AddTopic
+	clients map[*sseClient]struct{}
+	ring    []*SSEEvent
+	next    int // index of the next event within the ring buffer
+}
+
+type sseClient struct {
+	queue  chan *SSEEvent
+	kicked chan struct{} // closed when the client is disconnected by the SSEHub
+}
+
+// NewSSEHub creates a SSEHub with the default settings:
+// heartbeat every 15 seconds, replay the last 100 events per topic,
+// queue up to 64 events per client and drop the events of the slow clients.
+func NewSSEHub(gw Writer, topics ...string) *SSEHub {
+	h := &SSEHub{
+		Writer:         gw,
+		Heartbeat:      15 * time.Second,
+		ReplaySize:     100,
+		QueueSize:      64,
+		DisconnectSlow: false,
+		topics:         make(map[string]*sseTopic, len(topics)),
+		clients:        atomic.Int64{},
+		published:      atomic.Uint64{},
+		dropped:        atomic.Uint64{},
+		kicked:         atomic.Uint64{},
+		seq:            0,
+		mu:             sync.Mutex{},
+	}
+	for _, t := range topics {
+		h.AddTopic(t, nil)
+	}
+	return h
+}
+
+// NewSSEHub creates a SSEHub (see NewSSEHub) and exports its metrics to Prometheus.
+// The name distinguishes the metrics of several SSEHubs.
+func (g *Garcon) NewSSEHub(name string, topics ...string) *SSEHub {
+	h := NewSSEHub(g.Writer, topics...)
+	prometheus.MustRegister(h.Collector(g.ServerName, name))
+	return h
+}
+
+// AddTopic declares a topic. The optional TokenChecker authorizes the subscriptions:
+// the request must have a valid token (see TokenChecker.Vet).
+// AddTopic also replaces the TokenChecker of an existing topic.
+func (h *SSEHub) AddTopic(topic string, checker TokenChecker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// the Vet middleware is built once (not at every subscription)
+	var vet http.Handler
+	if checker != nil {
+		vet = checker.Vet(http.HandlerFunc(h.next))
+	}
+
+	if t, ok := h.topics[topic]; ok {
+		t.vet = vet
+		return
+	}
+	h.topics[topic] = &sseTopic{
+		vet:     vet,
+		clients: make(map[*sseClient]struct{}),
+		ring:    nil,
+		next:    0,
+	}
+}
+
+// Connected returns the number of connected clients.
+func (h *SSEHub) Connected() int { return int(h.clients.Load()) }
+
+// Publish broadcasts the data to the clients subscribed to the topic.
+// The event (can be empty) is the SSE "event" field: the browser listens it with
+// es.addEventListener(event, ...) instead of es.onmessage.
+// Publish returns the event ID, or zero when the topic is unknown.
+func (h *SSEHub) Publish(topic, event, data string) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	t, ok := h.topics[topic]
+	if !ok {
+		log.Warn("SSEHub: cannot publish on unknown topic", topic)
+		return 0
+	}
+
+	h.seq++
+	e := &SSEEvent{Topic: topic, ID: h.seq, frame: encodeSSE(h.seq, event, data)}
+	h.published.Add(1)
+
+	if h.ReplaySize > 0 {
+		if len(t.ring) < h.ReplaySize {
+			t.ring = append(t.ring, e)
+		} else {
+			t.ring[t.next] = e
+		}
+		t.next = (t.next + 1) % h.ReplaySize
+	}
+
+	for c := range t.clients {
+		select {
+		case c.queue <- e:
+		default:
+			h.dropped.Add(1)
+			if h.DisconnectSlow {
+				h.kick(c)
+			}
+		}
+	}
+
+	return e.ID
+}
+
+// PublishJSON is the same as Publish but serializes v in JSON
+// (using easyjson when implemented by v).
+func (h *SSEHub) PublishJSON(topic, event string, v any) (uint64, error) {
+	data, err := marshalJSON([]any{v})
+	if err != nil {
+		return 0, err
+	}
+	return h.Publish(topic, event, string(data)), nil
+}
+
+// kick disconnects a slow client: must be called with h.mu locked.
+func (h *SSEHub) kick(c *sseClient) {
+	for _, t := range h.topics {
+		delete(t.clients, c)
+	}
+	close(c.kicked)
+	h.kicked.Add(1)
+}
+
+// encodeSSE formats the event: one "data" field per line of data.
+func encodeSSE(id uint64, event, data string) []byte {
+	var b bytes.Buffer
+	b.WriteString("id: ")
+	b.WriteString(strconv.FormatUint(id, 10))
+	b.WriteByte('\n')
+	if event != "" {
+		b.WriteString("event: ")
+		b.WriteString(strings.NewReplacer("\r", "", "\n", "").Replace(event))
+		b.WriteByte('\n')
+	}
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	for _, line := range strings.Split(data, "\n") {
+		b.WriteString("data: ")
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	b.WriteByte('\n')
+	return b.Bytes()
+}
+
+// ServeHTTP subscribes the client to the topics of the query parameter "topic".
+// The subscription to a protected topic goes through the TokenChecker.Vet middleware.
+func (h *SSEHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	topics := uniqueTopics(r.URL.Query()["topic"])
+	if len(topics) == 0 {
+		h.Writer.WriteErr(w, r, 0, ErrSSETopic)
+		return
+	}
+
+	sub := &sseSubscription{topics: topics, vets: make([]http.Handler, 0, len(topics))}
+
+	h.mu.Lock()
+	for _, name := range topics {
+		t, ok := h.topics[name]
+		if !ok {
+			h.mu.Unlock()
+			h.Writer.WriteErr(w, r, 0, ErrSSETopic, "topic", name)
+			return
+		}
+		if t.vet != nil {
+			sub.vets = append(sub.vets, t.vet)
+		}
+	}
+	h.mu.Unlock()
+
+	ctx := context.WithValue(r.Context(), sseSubscriptionKey{}, sub)
+	h.next(w, r.WithContext(ctx))
+}
+
+// sseSubscription is the state of a subscription going through the Vet middlewares
+// of the protected topics, stored within the request context.
+type sseSubscription struct {
+	topics []string
+	vets   []http.Handler // remaining Vet middlewares
+}
+
+type sseSubscriptionKey struct{}
+
+// next is the handler following each Vet middleware:
+// next calls the next Vet middleware, and finally serves the subscription.
+func (h *SSEHub) next(w http.ResponseWriter, r *http.Request) {
+	sub, ok := r.Context().Value(sseSubscriptionKey{}).(*sseSubscription)
+	if !ok {
+		h.Writer.WriteErr(w, r, 0, ErrSSETopic)
+		return
+	}
+	if len(sub.vets) > 0 {
+		vet := sub.vets[0]
+		sub.vets = sub.vets[1:]
+		vet.ServeHTTP(w, r)
+		return
+	}
+	h.serve(w, r, sub.topics)
+}
+
+// uniqueTopics removes the duplicated topics (the events would be replayed several times).
+func uniqueTopics(topics []string) []string {
+	unique := topics[:0:0]
+	for _, t := range topics {
+		if !slices.Contains(unique, t) {
+			unique = append(unique, t)
+		}
+	}
+	return unique
+}
+
+// subscribe registers the client and returns the events to replay.
+// Both are done with the same lock: no event is lost or duplicated between the replay and the queue.
+func (h *SSEHub) subscribe(topics []string, lastID uint64, replay bool) (*sseClient, []*SSEEvent) {
+	c := &sseClient{
+		queue:  make(chan *SSEEvent, max(h.QueueSize, 1)),
+		kicked: make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var missed []*SSEEvent
+	for _, name := range topics {
+		t := h.topics[name]
+		t.clients[c] = struct{}{}
+		if replay {
+			for _, e := range t.ring {
+				if e.ID > lastID {
+					missed = append(missed, e)
+				}
+			}
+		}
+	}
+
+	sort.Slice(missed, func(i, j int) bool { return missed[i].ID < missed[j].ID })
+	return c, missed
+}
+
+func (h *SSEHub) unsubscribe(c *sseClient, topics []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, name := range topics {
+		delete(h.topics[name].clients, c)
+	}
+}
+
+func (h *SSEHub) serve(w http.ResponseWriter, r *http.Request, topics []string) {
+	lastID, err := strconv.ParseUint(r.Header.Get("Last-Event-ID"), 10, 64)
+	replay := (err == nil)
+
+	c, missed := h.subscribe(topics, lastID, replay)
+	defer h.unsubscribe(c, topics)
+
+	h.clients.Add(1)
+	defer h.clients.Add(-1)
+
+	w.Header().Set("Content-Type", MediaTypeEventStream)
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Accel-Buffering", "no") // disable the Nginx buffering
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	send := func(frame []byte) bool {
+		extendWriteDeadline(rc)
+		if _, err := w.Write(frame); err != nil {
+			return false
+		}
+		return rc.Flush() == nil
+	}
+
+	if !send([]byte(": connected\n\n")) {
+		return
+	}
+	for _, e := range missed {
+		if !send(e.frame) {
+			return
+		}
+		lastID = e.ID
+	}
+
+	period := h.Heartbeat
+	if period <= 0 {
+		period = 15 * time.Second
+	}
+	heartbeat := time.NewTicker(period)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-c.kicked:
+			log.Info("SSEHub: disconnect slow client", ipMethodURLSafe(r))
+			return
+		case <-heartbeat.C:
+			if !send([]byte(": heartbeat\n\n")) {
+				return
+			}
+		case e := <-c.queue:
+			if e.ID <= lastID {
+				continue // already replayed
+			}
+			if !send(e.frame) {
+				return
+			}
+		}
+	}
+}
+
+// --------------------------------------
+// Prometheus metrics
+
+type sseHubCollector struct {
+	hub       *SSEHub
+	clients   *prometheus.Desc
+	published *prometheus.Desc
+	dropped   *prometheus.Desc
+	kicked    *prometheus.Desc
+}
+
+// Collector returns a prometheus.Collector exporting the connected clients
+// and the published, dropped and disconnected counters.
+func (h *SSEHub) Collector(namespace ServerName, name string) prometheus.Collector {
+	labels := prometheus.Labels{"hub": name}
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(string(namespace), "sse", metric), help, nil, labels)
+	}
+
+	return &sseHubCollector{
+		hub:       h,
+		clients:   desc("clients", "Number of connected clients."),
+		published: desc("published_total", "Number of published events."),
+		dropped:   desc("dropped_total", "Number of events not delivered to a slow client."),
+		kicked:    desc("disconnected_total", "Number of slow clients disconnected."),
+	}
+}
+
+func (c *sseHubCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.clients
+	ch <- c.published
+	ch <- c.dropped
+	ch <- c.kicked
+}
+
+func (c *sseHubCollector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(c.clients, prometheus.GaugeValue, float64(c.hub.clients.Load()))
+	ch <- prometheus.MustNewConstMetric(c.published, prometheus.CounterValue, float64(c.hub.published.Load()))
+	ch <- prometheus.MustNewConstMetric(c.dropped, prometheus.CounterValue, float64(c.hub.dropped.Load()))
+	ch <- prometheus.MustNewConstMetric(c.kicked, prometheus.CounterValue, float64(c.hub.kicked.Load()))
+}
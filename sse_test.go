@@ -0,0 +1,333 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/teal-finance/garcon"
+)
+
+// sseClient reads the SSE frames (without the comments) of one connection.
+type sseClient struct {
+	resp  *http.Response
+	lines *bufio.Scanner
+}
+
+func connectSSE(t *testing.T, url, lastEventID string) *sseClient {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	return &sseClient{resp: resp, lines: bufio.NewScanner(resp.Body)}
+}
+
+// next returns the next frame, skipping the comments when skipComments is true.
+func (c *sseClient) next(t *testing.T, skipComments bool) string {
+	t.Helper()
+
+	var frame []string
+	for c.lines.Scan() {
+		line := c.lines.Text()
+		if line == "" {
+			if len(frame) > 0 {
+				return strings.Join(frame, "\n")
+			}
+			continue
+		}
+		if skipComments && line[0] == ':' {
+			continue
+		}
+		frame = append(frame, line)
+	}
+	t.Fatal("stream closed:", c.lines.Err())
+	return ""
+}
+
+func waitConnected(t *testing.T, hub *garcon.SSEHub, n int) {
+	t.Helper()
+	for range 200 {
+		if hub.Connected() == n {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("Connected=%d want %d", hub.Connected(), n)
+}
+
+func TestSSEHub_Publish(t *testing.T) {
+	t.Parallel()
+
+	hub := garcon.NewSSEHub("", "btc", "eth")
+	srv := httptest.NewServer(hub)
+	t.Cleanup(srv.Close)
+
+	c := connectSSE(t, srv.URL+"?topic=btc", "")
+	if got := c.resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("Content-Type=%q", got)
+	}
+	waitConnected(t, hub, 1)
+
+	hub.Publish("eth", "", "not subscribed")
+	hub.Publish("btc", "tick", "line1\nline2")
+	if _, err := hub.PublishJSON("btc", "", map[string]int{"price": 42000}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"id: 2\nevent: tick\ndata: line1\ndata: line2",
+		"id: 3\ndata: {\"price\":42000}",
+	}
+	for _, w := range want {
+		if got := c.next(t, true); got != w {
+			t.Errorf("frame=%q want %q", got, w)
+		}
+	}
+
+	if id := hub.Publish("unknown", "", "x"); id != 0 {
+		t.Errorf("Publish on unknown topic returned ID %d", id)
+	}
+}
+
+func TestSSEHub_Replay(t *testing.T) {
+	t.Parallel()
+
+	hub := garcon.NewSSEHub("", "btc", "eth")
+	hub.ReplaySize = 3
+	srv := httptest.NewServer(hub)
+	t.Cleanup(srv.Close)
+
+	for i := range 5 {
+		hub.Publish("btc", "", "b"+string(rune('0'+i))) // IDs 1..5
+	}
+	hub.Publish("eth", "", "e") // ID 6
+
+	// ID 2 is out of the ring buffer: only the last 3 btc events are replayed
+	c := connectSSE(t, srv.URL+"?topic=btc&topic=eth", "1")
+	want := []string{"id: 3\ndata: b2", "id: 4\ndata: b3", "id: 5\ndata: b4", "id: 6\ndata: e"}
+	for _, w := range want {
+		if got := c.next(t, true); got != w {
+			t.Errorf("frame=%q want %q", got, w)
+		}
+	}
+
+	waitConnected(t, hub, 1)
+	hub.Publish("eth", "", "live")
+	if got := c.next(t, true); got != "id: 7\ndata: live" {
+		t.Errorf("live frame=%q", got)
+	}
+}
+
+func TestSSEHub_Heartbeat(t *testing.T) {
+	t.Parallel()
+
+	hub := garcon.NewSSEHub("", "btc")
+	hub.Heartbeat = 20 * time.Millisecond
+	srv := httptest.NewServer(hub)
+	t.Cleanup(srv.Close)
+
+	c := connectSSE(t, srv.URL+"?topic=btc", "")
+	if got := c.next(t, false); got != ": connected" {
+		t.Errorf("first frame=%q", got)
+	}
+	if got := c.next(t, false); got != ": heartbeat" {
+		t.Errorf("second frame=%q", got)
+	}
+}
+
+func TestSSEHub_InvalidTopic(t *testing.T) {
+	t.Parallel()
+
+	hub := garcon.NewSSEHub("", "btc")
+
+	for _, url := range []string{"/events", "/events?topic=btc&topic=xxx"} {
+		w := httptest.NewRecorder()
+		hub.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), `"sse_topic"`) {
+			t.Errorf("%s: status=%d body=%s", url, w.Code, w.Body.String())
+		}
+	}
+}
+
+// headerChecker is a TokenChecker accepting the requests having the header "Token: ok".
+type headerChecker struct{}
+
+func (headerChecker) Set(next http.Handler) http.Handler { return next }
+func (headerChecker) Chk(next http.Handler) http.Handler { return next }
+func (headerChecker) Cookie(int) *http.Cookie            { return nil }
+func (headerChecker) Vet(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Token") != "ok" {
+			garcon.WriteErr(w, r, http.StatusUnauthorized, "invalid token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func TestSSEHub_TokenChecker(t *testing.T) {
+	t.Parallel()
+
+	hub := garcon.NewSSEHub("", "public")
+	hub.AddTopic("private", headerChecker{})
+
+	cases := []struct {
+		url    string
+		token  string
+		status int
+	}{
+		{"/?topic=public", "", http.StatusOK},
+		{"/?topic=public&topic=private", "", http.StatusUnauthorized},
+		{"/?topic=private", "bad", http.StatusUnauthorized},
+		{"/?topic=private", "ok", http.StatusOK},
+	}
+
+	for _, c := range cases {
+		ctx, cancel := context.WithCancel(context.Background())
+		r := httptest.NewRequest(http.MethodGet, c.url, nil).WithContext(ctx)
+		r.Header.Set("Token", c.token)
+		w := httptest.NewRecorder()
+
+		if c.status == http.StatusOK {
+			cancel() // the subscription returns immediately
+		}
+		hub.ServeHTTP(w, r)
+		cancel()
+
+		if w.Code != c.status {
+			t.Errorf("%s token=%q: status=%d want %d", c.url, c.token, w.Code, c.status)
+		}
+	}
+}
+
+// countingChecker is a headerChecker counting the Vet middlewares built.
+type countingChecker struct {
+	headerChecker
+	built *atomic.Int32
+}
+
+func (c countingChecker) Vet(next http.Handler) http.Handler {
+	c.built.Add(1)
+	return c.headerChecker.Vet(next)
+}
+
+func TestSSEHub_VetBuiltOnce(t *testing.T) {
+	t.Parallel()
+
+	var built atomic.Int32
+	hub := garcon.NewSSEHub("")
+	hub.AddTopic("a", countingChecker{headerChecker{}, &built})
+	hub.AddTopic("b", countingChecker{headerChecker{}, &built})
+
+	for range 3 {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel() // the subscription returns immediately
+		r := httptest.NewRequest(http.MethodGet, "/?topic=a&topic=b", nil).WithContext(ctx)
+		r.Header.Set("Token", "ok")
+		w := httptest.NewRecorder()
+		hub.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Errorf("status=%d body=%s", w.Code, w.Body.String())
+		}
+	}
+
+	if n := built.Load(); n != 2 {
+		t.Errorf("Vet middleware built %d times, want 2 (once per topic)", n)
+	}
+}
+
+func TestSSEHub_DuplicateTopic(t *testing.T) {
+	t.Parallel()
+
+	hub := garcon.NewSSEHub("", "btc")
+	srv := httptest.NewServer(hub)
+	t.Cleanup(srv.Close)
+
+	hub.Publish("btc", "", "b1") // ID 1
+	hub.Publish("btc", "", "b2") // ID 2
+
+	c := connectSSE(t, srv.URL+"?topic=btc&topic=btc", "0")
+	for _, w := range []string{"id: 1\ndata: b1", "id: 2\ndata: b2"} {
+		if got := c.next(t, true); got != w {
+			t.Errorf("frame=%q want %q", got, w)
+		}
+	}
+
+	waitConnected(t, hub, 1)
+	hub.Publish("btc", "", "live")
+	if got := c.next(t, true); got != "id: 3\ndata: live" {
+		t.Errorf("live frame=%q (replayed events duplicated?)", got)
+	}
+}
+
+// blockedWriter simulates a slow client: Write blocks until unblock is closed.
+type blockedWriter struct {
+	*httptest.ResponseRecorder
+	unblock chan struct{}
+}
+
+func (b blockedWriter) Write(p []byte) (int, error) {
+	<-b.unblock
+	return len(p), nil
+}
+
+func TestSSEHub_SlowClient(t *testing.T) {
+	t.Parallel()
+
+	for _, disconnect := range []bool{false, true} {
+		hub := garcon.NewSSEHub("", "btc")
+		hub.QueueSize = 2
+		hub.DisconnectSlow = disconnect
+
+		ctx, cancel := context.WithCancel(context.Background())
+		w := blockedWriter{httptest.NewRecorder(), make(chan struct{})}
+		done := make(chan struct{})
+		go func() {
+			hub.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?topic=btc", nil).WithContext(ctx))
+			close(done)
+		}()
+		waitConnected(t, hub, 1)
+
+		// the handler is blocked writing ": connected": the queue fills up
+		for range 5 {
+			hub.Publish("btc", "", "x")
+		}
+		close(w.unblock)
+
+		if disconnect {
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("slow client not disconnected")
+			}
+		} else {
+			waitConnected(t, hub, 1)
+			cancel()
+			<-done
+		}
+		cancel()
+		waitConnected(t, hub, 0)
+	}
+}
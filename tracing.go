@@ -0,0 +1,418 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/teal-finance/garcon/gg"
+)
+
+// W3C Trace Context headers: https://www.w3.org/TR/trace-context/
+const (
+	traceparentHeader = "traceparent"
+	tracestateHeader  = "tracestate"
+	maxTracestateLen  = 512 // vendors may drop tracestate longer than 512 characters
+	sampledFlag       = 0x01
+)
+
+// Default batch settings of the Tracer.
+const (
+	defaultBatchSize     = 512
+	defaultFlushInterval = 5 * time.Second
+)
+
+type (
+	// TraceID is the W3C trace-id, a 16-byte array encoded as 32 hexadecimal digits.
+	TraceID [16]byte
+
+	// SpanID is the W3C parent-id, a 8-byte array encoded as 16 hexadecimal digits.
+	SpanID [8]byte
+)
+
+func (id TraceID) String() string { return hex.EncodeToString(id[:]) }
+func (id SpanID) String() string  { return hex.EncodeToString(id[:]) }
+
+// IsValid returns false when all bytes are zero (forbidden by the W3C specification).
+func (id TraceID) IsValid() bool { return id != TraceID{} }
+
+// IsValid returns false when all bytes are zero (forbidden by the W3C specification).
+func (id SpanID) IsValid() bool { return id != SpanID{} }
+
+// SpanKind follows the OpenTelemetry values.
+type SpanKind int
+
+const (
+	SpanKindUnspecified SpanKind = 0
+	SpanKindInternal    SpanKind = 1
+	SpanKindServer      SpanKind = 2
+	SpanKindClient      SpanKind = 3
+)
+
+func (k SpanKind) String() string {
+	switch k {
+	case SpanKindInternal:
+		return "internal"
+	case SpanKindServer:
+		return "server"
+	case SpanKindClient:
+		return "client"
+	default:
+		return "unspecified"
+	}
+}
+
+// Span is a unit of work compatible with the OpenTelemetry data model.
+type Span struct {
+	Start      time.Time
+	End        time.Time
+	attributes map[string]any
+	Name       string
+	TraceState string
+	TraceID    TraceID
+	SpanID     SpanID
+	ParentID   SpanID
+	Kind       SpanKind
+	StatusCode int // HTTP status code, 5xx means error
+	Sampled    bool
+	mu         sync.Mutex
+}
+
+// SetAttribute records a key/value within the span.
+// SetAttribute is safe for concurrent use and does nothing when span is nil.
+func (s *Span) SetAttribute(key string, value any) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.attributes[key] = value
+	s.mu.Unlock()
+}
+
+// Attributes returns a copy of the span attributes.
+func (s *Span) Attributes() map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	attributes := make(map[string]any, len(s.attributes))
+	for k, v := range s.attributes {
+		attributes[k] = v
+	}
+	return attributes
+}
+
+// Traceparent returns the W3C "traceparent" header value.
+func (s *Span) Traceparent() string {
+	flags := "00"
+	if s.Sampled {
+		flags = "01"
+	}
+	return "00-" + s.TraceID.String() + "-" + s.SpanID.String() + "-" + flags
+}
+
+// SpanExporter sends the ended spans to a tracing backend.
+// See NewStdoutExporter and NewOTLPExporter.
+type SpanExporter interface {
+	Export(spans []*Span) error
+}
+
+// Tracer starts the spans, propagates the W3C Trace Context
+// and exports the sampled spans in batch.
+type Tracer struct {
+	exporter      SpanExporter
+	serviceName   string
+	batch         []*Span
+	done          chan struct{} // closed by Shutdown
+	BatchSize     int
+	FlushInterval time.Duration
+	mu            sync.Mutex
+	once          sync.Once
+	stopOnce      sync.Once
+}
+
+// NewTracer creates a Tracer using the ServerName as service name.
+func (g *Garcon) NewTracer(exporter SpanExporter) *Tracer {
+	return NewTracer(g.ServerName.String(), exporter)
+}
+
+// NewTracer creates a Tracer exporting the spans in batch.
+// The spans are exported every FlushInterval or when BatchSize spans are pending.
+func NewTracer(serviceName string, exporter SpanExporter) *Tracer {
+	if exporter == nil {
+		log.Panic("garcon.NewTracer() requires a SpanExporter, see NewStdoutExporter()")
+	}
+
+	return &Tracer{
+		exporter:      exporter,
+		serviceName:   serviceName,
+		batch:         nil,
+		done:          make(chan struct{}),
+		BatchSize:     defaultBatchSize,
+		FlushInterval: defaultFlushInterval,
+		mu:            sync.Mutex{},
+		once:          sync.Once{},
+		stopOnce:      sync.Once{},
+	}
+}
+
+// ServiceName is exported within the OTLP resource attributes.
+func (t *Tracer) ServiceName() string { return t.serviceName }
+
+// Start creates a span, child of the span within ctx (if any) or a new root span.
+func (t *Tracer) Start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	span := newSpan(name, kind)
+
+	if parent := SpanFromCtx(ctx); parent != nil {
+		span.TraceID = parent.TraceID
+		span.ParentID = parent.SpanID
+		span.TraceState = parent.TraceState
+		span.Sampled = parent.Sampled
+	}
+
+	if !span.TraceID.IsValid() {
+		copy(span.TraceID[:], gg.RandomBytes(len(span.TraceID)))
+	}
+
+	return context.WithValue(ctx, spanKey, span), span
+}
+
+// Finish ends the span and queues it for export (only if sampled).
+func (t *Tracer) Finish(span *Span) {
+	span.End = time.Now()
+	if !span.Sampled {
+		return
+	}
+
+	t.once.Do(func() { go t.flushPeriodically() })
+
+	t.mu.Lock()
+	t.batch = append(t.batch, span)
+	full := len(t.batch) >= t.BatchSize
+	t.mu.Unlock()
+
+	if full {
+		go t.Flush()
+	}
+}
+
+// Flush exports the pending spans.
+// See also Shutdown to call before stopping the server.
+func (t *Tracer) Flush() {
+	t.mu.Lock()
+	spans := t.batch
+	t.batch = nil
+	t.mu.Unlock()
+
+	if len(spans) == 0 {
+		return
+	}
+
+	if err := t.exporter.Export(spans); err != nil {
+		log.Warnf("Tracer: cannot export %d spans: %v", len(spans), err)
+	}
+}
+
+// Shutdown stops the periodic flush and exports the pending spans.
+// Shutdown returns ctx.Err() when ctx is done before the end of the export.
+// The spans finished after Shutdown are exported only by Flush.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	t.once.Do(func() {}) // prevent starting the periodic flush after Shutdown
+	t.stopOnce.Do(func() { close(t.done) })
+
+	flushed := make(chan struct{})
+	go func() {
+		t.Flush()
+		close(flushed)
+	}()
+
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *Tracer) flushPeriodically() {
+	ticker := time.NewTicker(t.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.done:
+			return
+		case <-ticker.C:
+			t.Flush()
+		}
+	}
+}
+
+// Middleware starts a server span for each incoming request.
+// The span continues the trace from the "traceparent" and "tracestate" request headers.
+// The span records the route, the status code and the permission (see Perm.PutInCtx).
+// The span name is the method and the route pattern when the router is chi
+// (the middleware must be registered with chi's Use), else the sanitized URL path.
+// The "traceparent" is also set in the response header.
+func (t *Tracer) Middleware(next http.Handler) http.Handler {
+	log.Info("MiddlewareTracing starts a span per request, service=" + t.serviceName)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		span := newSpan(r.Method, SpanKindServer) // the route is known after routing
+		span.Sampled = true
+		if !parseTraceparent(r.Header.Get(traceparentHeader), span) {
+			copy(span.TraceID[:], gg.RandomBytes(len(span.TraceID)))
+		}
+		if span.ParentID.IsValid() {
+			span.TraceState = tracestate(r.Header.Get(tracestateHeader))
+		}
+
+		span.SetAttribute("http.request.method", r.Method)
+		span.SetAttribute("client.address", remoteAddr(r))
+		span.SetAttribute("user_agent.original", safeHeader(r, "User-Agent"))
+
+		w.Header().Set(traceparentHeader, span.Traceparent())
+		record := newResponseRecorder(w)
+
+		ctx := context.WithValue(r.Context(), spanKey, span)
+		next.ServeHTTP(record, r.WithContext(ctx))
+
+		rt := route(r)
+		span.Name = r.Method + " " + rt
+		span.SetAttribute("http.route", rt)
+		span.StatusCode = record.StatusCode
+		span.SetAttribute("http.response.status_code", record.StatusCode)
+		t.Finish(span)
+	})
+}
+
+// RTMiddleware starts a client span for each outgoing request
+// and propagates the W3C Trace Context headers.
+// RTMiddleware is a gg.RTMiddleware:
+//
+//	rt := gg.NewRTChain(tracer.RTMiddleware).Then(nil)
+//	client := &http.Client{Transport: rt}
+func (t *Tracer) RTMiddleware(next http.RoundTripper) http.RoundTripper {
+	return gg.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		ctx, span := t.Start(r.Context(), r.Method+" "+r.URL.Host, SpanKindClient)
+		if SpanFromCtx(r.Context()) == nil {
+			span.Sampled = true // new root span
+		}
+
+		span.SetAttribute("http.request.method", r.Method)
+		span.SetAttribute("server.address", r.URL.Host)
+		span.SetAttribute("url.path", r.URL.Path)
+
+		r = r.Clone(ctx) // RoundTripper must not modify the original request
+		r.Header.Set(traceparentHeader, span.Traceparent())
+		if span.TraceState != "" {
+			r.Header.Set(tracestateHeader, span.TraceState)
+		}
+
+		resp, err := next.RoundTrip(r)
+		switch {
+		case err != nil:
+			span.SetAttribute("error.type", err.Error())
+			span.StatusCode = http.StatusBadGateway
+		default:
+			span.StatusCode = resp.StatusCode
+			span.SetAttribute("http.response.status_code", resp.StatusCode)
+		}
+
+		t.Finish(span)
+		return resp, err
+	})
+}
+
+func newSpan(name string, kind SpanKind) *Span {
+	span := &Span{
+		Start:      time.Now(),
+		End:        time.Time{},
+		attributes: make(map[string]any, 8),
+		Name:       name,
+		TraceState: "",
+		TraceID:    TraceID{},
+		SpanID:     SpanID{},
+		ParentID:   SpanID{},
+		Kind:       kind,
+		StatusCode: 0,
+		Sampled:    false,
+		mu:         sync.Mutex{},
+	}
+	copy(span.SpanID[:], gg.RandomBytes(len(span.SpanID)))
+	return span
+}
+
+// parseTraceparent decodes the traceparent header: version-traceid-parentid-flags.
+// parseTraceparent returns false if the header is absent or malformed.
+func parseTraceparent(header string, span *Span) bool {
+	const length = 2 + 1 + 32 + 1 + 16 + 1 + 2
+	if len(header) < length || (len(header) > length && header[length] != '-') {
+		return false
+	}
+	if header[2] != '-' || header[35] != '-' || header[52] != '-' {
+		return false
+	}
+
+	version, err := hex.DecodeString(header[:2])
+	if err != nil || version[0] == 0xff {
+		return false
+	}
+	if version[0] == 0 && len(header) != length {
+		return false
+	}
+
+	var traceID TraceID
+	if _, err = hex.Decode(traceID[:], []byte(header[3:35])); err != nil || !traceID.IsValid() {
+		return false
+	}
+
+	var parentID SpanID
+	if _, err = hex.Decode(parentID[:], []byte(header[36:52])); err != nil || !parentID.IsValid() {
+		return false
+	}
+
+	flags, err := hex.DecodeString(header[53:55])
+	if err != nil {
+		return false
+	}
+
+	span.TraceID = traceID
+	span.ParentID = parentID
+	span.Sampled = (flags[0] & sampledFlag) != 0
+	return true
+}
+
+// tracestate sanitizes the tracestate header to be propagated as is.
+func tracestate(header string) string {
+	if len(header) > maxTracestateLen || gg.Printable(header) >= 0 {
+		return ""
+	}
+	return strings.TrimSpace(header)
+}
+
+// --------------------------------------
+// Read/write span to/from context
+
+//nolint:gochecknoglobals // spanKey is a Context key and need to be global
+var spanKey struct{ span byte }
+
+// SpanFromCtx returns the current span, or nil if absent.
+func SpanFromCtx(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey).(*Span)
+	return span
+}
+
+// TraceIDFromCtx returns the current trace-id in hexadecimal, or an empty string if absent.
+func TraceIDFromCtx(ctx context.Context) string {
+	if span := SpanFromCtx(ctx); span != nil {
+		return span.TraceID.String()
+	}
+	return ""
+}
@@ -0,0 +1,259 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// StdoutExporter writes the spans as JSON lines, useful in development.
+type StdoutExporter struct {
+	w  io.Writer
+	mu sync.Mutex
+}
+
+// NewStdoutExporter creates a StdoutExporter writing to the optional writer (default is os.Stdout).
+func NewStdoutExporter(w ...io.Writer) *StdoutExporter {
+	e := &StdoutExporter{w: os.Stdout, mu: sync.Mutex{}}
+	if len(w) > 0 && w[0] != nil {
+		e.w = w[0]
+	}
+	return e
+}
+
+type stdoutSpan struct {
+	Attributes map[string]any `json:"attributes,omitempty"`
+	Name       string         `json:"name"`
+	Kind       string         `json:"kind"`
+	TraceID    string         `json:"trace_id"`
+	SpanID     string         `json:"span_id"`
+	ParentID   string         `json:"parent_span_id,omitempty"`
+	TraceState string         `json:"tracestate,omitempty"`
+	Start      time.Time      `json:"start"`
+	Duration   string         `json:"duration"`
+	Status     int            `json:"status,omitempty"`
+}
+
+// Export writes one JSON line per span.
+func (e *StdoutExporter) Export(spans []*Span) error {
+	buf := make([]byte, 0, 256*len(spans))
+	for _, s := range spans {
+		parent := ""
+		if s.ParentID.IsValid() {
+			parent = s.ParentID.String()
+		}
+		b, err := json.Marshal(stdoutSpan{
+			Attributes: s.Attributes(),
+			Name:       s.Name,
+			Kind:       s.Kind.String(),
+			TraceID:    s.TraceID.String(),
+			SpanID:     s.SpanID.String(),
+			ParentID:   parent,
+			TraceState: s.TraceState,
+			Start:      s.Start,
+			Duration:   s.End.Sub(s.Start).String(),
+			Status:     s.StatusCode,
+		})
+		if err != nil {
+			return fmt.Errorf("StdoutExporter: %w", err)
+		}
+		buf = append(buf, b...)
+		buf = append(buf, '\n')
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, err := e.w.Write(buf)
+	return err
+}
+
+// OTLPExporter sends the spans to an OpenTelemetry collector
+// using the OTLP/HTTP protocol with JSON encoding.
+type OTLPExporter struct {
+	client   *http.Client
+	headers  map[string]string
+	endpoint string
+	service  string
+}
+
+// NewOTLPExporter creates an OTLPExporter.
+// The endpoint is usually "http://localhost:4318/v1/traces".
+// The optional headers are key/value pairs (for authentication by example).
+func NewOTLPExporter(endpoint, serviceName string, headers ...string) *OTLPExporter {
+	if len(headers)%2 != 0 {
+		log.Panic("garcon.NewOTLPExporter() wants headers as key/value pairs, but got", len(headers))
+	}
+
+	e := &OTLPExporter{
+		client:   &http.Client{Timeout: 10 * time.Second},
+		headers:  make(map[string]string, len(headers)/2),
+		endpoint: endpoint,
+		service:  serviceName,
+	}
+
+	for i := 0; i < len(headers); i += 2 {
+		e.headers[headers[i]] = headers[i+1]
+	}
+
+	return e
+}
+
+// Export sends the spans within one OTLP/HTTP request.
+func (e *OTLPExporter) Export(spans []*Span) error {
+	body, err := json.Marshal(e.request(spans))
+	if err != nil {
+		return fmt.Errorf("OTLPExporter: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("OTLPExporter: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("OTLPExporter: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("OTLPExporter: %s from %s", resp.Status, e.endpoint)
+	}
+	return nil
+}
+
+// OTLP/JSON data model (subset):
+// https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+type (
+	otlpRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name    string `json:"name"`
+		Version string `json:"version,omitempty"`
+	}
+	otlpSpan struct {
+		TraceID           string         `json:"traceId"`
+		SpanID            string         `json:"spanId"`
+		ParentSpanID      string         `json:"parentSpanId,omitempty"`
+		TraceState        string         `json:"traceState,omitempty"`
+		Name              string         `json:"name"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		Status            otlpStatus     `json:"status"`
+		Kind              SpanKind       `json:"kind"`
+	}
+	otlpStatus struct {
+		Message string `json:"message,omitempty"`
+		Code    int    `json:"code"` // 0=unset 1=ok 2=error
+	}
+	otlpKeyValue struct {
+		Value otlpAnyValue `json:"value"`
+		Key   string       `json:"key"`
+	}
+	otlpAnyValue struct {
+		StringValue *string  `json:"stringValue,omitempty"`
+		IntValue    *string  `json:"intValue,omitempty"` // int64 is encoded as string in JSON
+		DoubleValue *float64 `json:"doubleValue,omitempty"`
+		BoolValue   *bool    `json:"boolValue,omitempty"`
+	}
+)
+
+func (e *OTLPExporter) request(spans []*Span) otlpRequest {
+	list := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		list = append(list, toOTLPSpan(s))
+	}
+
+	return otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{
+				Attributes: []otlpKeyValue{otlpAttribute("service.name", e.service)},
+			},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: "github.com/teal-finance/garcon", Version: V},
+				Spans: list,
+			}},
+		}},
+	}
+}
+
+func toOTLPSpan(s *Span) otlpSpan {
+	parent := ""
+	if s.ParentID.IsValid() {
+		parent = s.ParentID.String()
+	}
+
+	attributes := s.Attributes()
+	kv := make([]otlpKeyValue, 0, len(attributes))
+	for k, v := range attributes {
+		kv = append(kv, otlpAttribute(k, v))
+	}
+
+	status := otlpStatus{Message: "", Code: 0}
+	if s.StatusCode >= 500 || (s.Kind == SpanKindClient && s.StatusCode >= 400) {
+		status = otlpStatus{Message: http.StatusText(s.StatusCode), Code: 2}
+	}
+
+	return otlpSpan{
+		TraceID:           s.TraceID.String(),
+		SpanID:            s.SpanID.String(),
+		ParentSpanID:      parent,
+		TraceState:        s.TraceState,
+		Name:              s.Name,
+		StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+		Attributes:        kv,
+		Status:            status,
+		Kind:              s.Kind,
+	}
+}
+
+func otlpAttribute(key string, value any) otlpKeyValue {
+	var v otlpAnyValue
+	switch val := value.(type) {
+	case string:
+		v.StringValue = &val
+	case bool:
+		v.BoolValue = &val
+	case int:
+		i := strconv.Itoa(val)
+		v.IntValue = &i
+	case int64:
+		i := strconv.FormatInt(val, 10)
+		v.IntValue = &i
+	case float64:
+		v.DoubleValue = &val
+	default:
+		str := fmt.Sprint(val)
+		v.StringValue = &str
+	}
+	return otlpKeyValue{Value: v, Key: key}
+}
@@ -0,0 +1,158 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/teal-finance/garcon"
+	"github.com/teal-finance/garcon/gg"
+)
+
+// collectorStub mimics an OpenTelemetry collector receiving OTLP/HTTP JSON.
+type collectorStub struct {
+	traceIDs []string
+	names    []string
+	mu       sync.Mutex
+}
+
+func (c *collectorStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []struct {
+					TraceID string `json:"traceId"`
+					Name    string `json:"name"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+
+	if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/json" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				c.traceIDs = append(c.traceIDs, s.TraceID)
+				c.names = append(c.names, s.Name)
+			}
+		}
+	}
+}
+
+func TestTracer(t *testing.T) {
+	t.Parallel()
+
+	collector := &collectorStub{}
+	otlp := httptest.NewServer(collector)
+	defer otlp.Close()
+
+	var downstreamTraceparent string
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downstreamTraceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer downstream.Close()
+
+	tracer := garcon.NewTracer("test", garcon.NewOTLPExporter(otlp.URL+"/v1/traces", "test"))
+	client := &http.Client{Transport: gg.NewRTChain(tracer.RTMiddleware).Then(nil)}
+
+	h := tracer.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req, _ := http.NewRequestWithContext(r.Context(), http.MethodGet, downstream.URL, http.NoBody)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Error("downstream:", err)
+		} else {
+			resp.Body.Close()
+		}
+		garcon.WriteErr(w, r, http.StatusTeapot, "short and stout")
+	}))
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	r := httptest.NewRequest(http.MethodGet, "/api/items", http.NoBody)
+	r.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if !strings.Contains(w.Body.String(), `"trace_id":"`+traceID+`"`) {
+		t.Error("WriteErr body misses the trace_id:", w.Body.String())
+	}
+	if !strings.HasPrefix(w.Header().Get("traceparent"), "00-"+traceID+"-") {
+		t.Error("response traceparent does not continue the trace:", w.Header().Get("traceparent"))
+	}
+	if !strings.HasPrefix(downstreamTraceparent, "00-"+traceID+"-") {
+		t.Error("outgoing traceparent does not propagate the trace:", downstreamTraceparent)
+	}
+
+	tracer.Flush()
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	if len(collector.traceIDs) != 2 {
+		t.Fatalf("collector got %d spans, want 2 (server and client)", len(collector.traceIDs))
+	}
+	for i, id := range collector.traceIDs {
+		if id != traceID {
+			t.Errorf("span %q got traceId=%s, want %s", collector.names[i], id, traceID)
+		}
+	}
+}
+
+func TestTracer_RouteAndShutdown(t *testing.T) {
+	t.Parallel()
+
+	collector := &collectorStub{}
+	otlp := httptest.NewServer(collector)
+	defer otlp.Close()
+
+	tracer := garcon.NewTracer("test", garcon.NewOTLPExporter(otlp.URL+"/v1/traces", "test"))
+	tracer.FlushInterval = time.Hour
+
+	router := chi.NewRouter()
+	router.Use(tracer.Middleware)
+	router.Get("/items/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	for _, path := range []string{"/items/1", "/items/2", "/items/3"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, http.NoBody))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := tracer.Shutdown(ctx); err != nil {
+		t.Fatal("Shutdown:", err)
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	if len(collector.names) != 3 {
+		t.Fatalf("Shutdown exported %d spans, want 3", len(collector.names))
+	}
+	for _, name := range collector.names {
+		if name != "GET /items/{id}" {
+			t.Errorf("span name %q, want the route pattern %q", name, "GET /items/{id}")
+		}
+	}
+}
@@ -210,14 +210,7 @@ func ServeVersion() func(w http.ResponseWriter, r *http.Request) {
 // writeJSON converts the version info from string slice to JSON.
 func writeJSON(w http.ResponseWriter) {
 	info.Ago = sinceLastCommit()
-	b, err := info.MarshalJSON()
-	if err != nil {
-		log.Warn("writeJSON MarshalJSON:", err)
-		w.WriteHeader(http.StatusNoContent)
-	} else {
-		w.Header().Set("Content-Type", "application/json")
-		w.Write(b)
-	}
+	WriteJSON(w, nil, info)
 }
 
 // writeHTML converts the version info from string slice to JSON.
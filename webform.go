@@ -9,14 +9,23 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/teal-finance/garcon/gg"
 )
 
+// webFormQueueSize is the number of web forms waiting to be notified.
+const webFormQueueSize = 100
+
 type WebForm struct {
 	Writer   Writer
 	Notifier gg.Notifier
+
+	// Redirect is the URL the browser is redirected to once the web form is received.
+	// The placeholder "{lang}" is replaced by the language negotiated
+	// from the "Accept-Language" header (see Locales.Middleware), e.g. "/{lang}/thanks".
 	Redirect string
 
 	// TextLimits are used as security limits
@@ -40,15 +49,22 @@ type WebForm struct {
 	// Zero (or negative) value disables this security check.
 	MaxBodyBytes int64
 
-	// MaxMDBytes includes the form fields and browser fingerprints.
+	// MaxMDBytes limits the size of the form fields and browser fingerprints
+	// sent to the notifier.
 	// Zero (or negative) value disables this security check.
 	MaxMDBytes int
 
+	// Privacy replaces the IP and the identifying headers by pseudonyms
+	// within the browser fingerprint. Nil disables the privacy mode.
+	Privacy *gg.Pseudonymizer
+
 	maxFieldNameLength int
 }
 
 func (g *Garcon) NewContactForm(redirectURL string) WebForm {
-	return NewContactForm(g.Writer, redirectURL)
+	wf := NewContactForm(g.Writer, redirectURL)
+	wf.Privacy = g.privacy
+	return wf
 }
 
 // NewContactForm initializes a new WebForm with the default contact-form settings.
@@ -61,6 +77,7 @@ func NewContactForm(gw Writer, redirectURL string) WebForm {
 		FileLimits:         DefaultFileSettings(),
 		MaxBodyBytes:       5555,
 		MaxMDBytes:         4000,
+		Privacy:            nil,
 		maxFieldNameLength: 0,
 	}
 }
@@ -112,12 +129,18 @@ func (wf *WebForm) init() {
 }
 
 // Notify returns a handler that
-// converts the received web-form into markdown format
-// and sends it to the notifierURL.
+// converts the received web-form into a gg.Message
+// and sends it to the notifierURL in background (the redirect does not wait the notifier).
+// Each notifier renders (and escapes) the message with its own markup (see gg.NotifyMessage).
+// If wf.Notifier is set, notifierURL is ignored and wf.Notifier is used as is
+// (e.g. a gg.AsyncNotifier with a spool directory).
 func (wf *WebForm) Notify(notifierURL string) func(w http.ResponseWriter, r *http.Request) {
 	wf.init()
 
-	notifier := gg.NewNotifier(notifierURL)
+	notifier := wf.Notifier
+	if notifier == nil {
+		notifier = gg.NewAsyncNotifier(gg.NewNotifier(notifierURL), webFormQueueSize, "")
+	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
 		if wf.MaxBodyBytes > 0 {
@@ -127,34 +150,87 @@ func (wf *WebForm) Notify(notifierURL string) func(w http.ResponseWriter, r *htt
 		err := r.ParseForm()
 		if err != nil {
 			log.Warn("WebForm ParseForm:", err)
-			wf.Writer.WriteErr(w, r, http.StatusBadRequest, "cannot parse the webform", "reason", err.Error())
+			wf.Writer.WriteErr(w, r, http.StatusBadRequest, ErrInvalidWebForm, "reason", err.Error())
 			return
 		}
 
-		md := wf.toMarkdown(r)
-		err = notifier.Notify(md)
+		err = gg.NotifyMessage(notifier, wf.toMessage(r))
 		if err != nil {
 			log.Warn("WebForm Notify:", err)
 		}
 
-		http.Redirect(w, r, wf.Redirect, http.StatusFound)
+		http.Redirect(w, r, wf.redirectURL(r), http.StatusFound)
 	}
 }
 
-func (wf *WebForm) toMarkdown(r *http.Request) string {
+// redirectURL replaces the "{lang}" placeholder by the negotiated language.
+// The placeholder is removed when the localization is disabled.
+func (wf *WebForm) redirectURL(r *http.Request) string {
+	if !strings.Contains(wf.Redirect, "{lang}") {
+		return wf.Redirect
+	}
+	lang := LanguageFromCtx(r.Context())
+	u := wf.Redirect
+	if lang == "" {
+		u = strings.ReplaceAll(u, "/{lang}/", "/")
+	}
+	return strings.ReplaceAll(u, "{lang}", lang)
+}
+
+// toMessage converts the form fields and the browser fingerprint into a gg.Message.
+// The values exceeding MaxMDBytes are trimmed.
+func (wf *WebForm) toMessage(r *http.Request) gg.Message {
 	log.Infof("WebForm with %d input fields", len(r.Form))
-	md := wf.formMD(r.Form) + FingerprintMD(r)
-	if extra := overflow25(len(md), wf.MaxMDBytes); extra > 0 {
-		md = md[:wf.MaxMDBytes] + "\n\n" +
-			"(trimmed last " + strconv.Itoa(extra) + " characters)"
+	if wf.Privacy != nil && gg.PseudonymizerFromCtx(r.Context()) == nil {
+		r = r.WithContext(gg.PutPseudonymizer(r.Context(), wf.Privacy))
+	}
+
+	msg := gg.Message{
+		Title:    "Web form",
+		Body:     "",
+		Fields:   append(wf.formFields(r.Form), FingerprintFields(r)...),
+		Links:    nil,
+		Severity: gg.SeverityInfo,
+	}
+
+	size := 0
+	for _, f := range msg.Fields {
+		size += len(f.Name) + len(f.Value)
+	}
+	if extra := overflow25(size, wf.MaxMDBytes); extra > 0 {
+		msg.Fields = trimFields(msg.Fields, wf.MaxMDBytes)
+		msg.Body = "(trimmed last " + strconv.Itoa(extra) + " characters)"
 	}
-	return md
+	return msg
 }
 
-func (wf *WebForm) formMD(fields url.Values) string {
-	md := ""
+// trimFields keeps the first fields within maxBytes, the last kept value is truncated.
+func trimFields(fields []gg.Field, maxBytes int) []gg.Field {
+	for i, f := range fields {
+		maxBytes -= len(f.Name)
+		if maxBytes < len(f.Value) {
+			if maxBytes > 0 {
+				fields[i].Value = strings.ToValidUTF8(f.Value[:maxBytes], "")
+				i++
+			}
+			return fields[:i]
+		}
+		maxBytes -= len(f.Value)
+	}
+	return fields
+}
 
-	for name, values := range fields {
+// formFields returns the valid form fields, sorted by name.
+func (wf *WebForm) formFields(form url.Values) []gg.Field {
+	names := make([]string, 0, len(form))
+	for name := range form {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var fields []gg.Field
+	for _, name := range names {
+		values := form[name]
 		if !wf.valid(name, values) {
 			continue
 		}
@@ -166,21 +242,17 @@ func (wf *WebForm) formMD(fields url.Values) string {
 			continue
 		}
 
-		if extra := overflow25(len(values[0]), maxLen); extra > 0 {
-			values[0] = values[0][:maxLen] + "\n" +
+		value := values[0]
+		if extra := overflow25(len(value), maxLen); extra > 0 {
+			value = strings.ToValidUTF8(value[:maxLen], "") + "\n" +
 				"(trimmed last " + strconv.Itoa(extra) + " characters)"
 			maxLines++
 		}
 
-		if md == "" { // no break line at first loop
-			md += "- **"
-		} else {
-			md += "\n" + "- **" // double star -> bold
-		}
-		md += name + "**: " + wf.bulletParagraph(values[0], maxLines)
+		fields = append(fields, gg.Field{Name: name, Value: paragraph(value, maxLines)})
 	}
 
-	return md
+	return fields
 }
 
 func (wf *WebForm) valid(name string, values []string) bool {
@@ -233,22 +305,18 @@ func overflow25(n, max int) int {
 	return 0
 }
 
-// Markdown encoding.
-const (
-	lineBreak    = "  " // trailing double space -> line break
-	bulletIndent = " "  // leading spaces -> bullet indent
-)
-
-func (wf *WebForm) bulletParagraph(str string, maxLines int) string {
-	md := ""
+// paragraph cleans the lines of str and drops the redundant blank lines.
+// paragraph keeps maxLines lines (zero or negative for unlimited).
+func paragraph(str string, maxLines int) string {
+	txt := ""
 
 	count := 0
 	blank := false
-	txt := gg.SplitCleanedLines(str)
-	for i := range txt {
+	lines := gg.SplitCleanedLines(str)
+	for i := range lines {
 		// skip top blank lines, redundant blank lines and bottom blank lines
-		if txt[i] == "" {
-			if md != "" {
+		if lines[i] == "" {
+			if txt != "" {
 				blank = true
 			}
 			continue
@@ -256,19 +324,19 @@ func (wf *WebForm) bulletParagraph(str string, maxLines int) string {
 
 		if blank {
 			blank = false
-			md += lineBreak + "\n\n" + bulletIndent
-		} else if md != "" {
-			md += lineBreak + "\n" + bulletIndent
+			txt += "\n\n"
+		} else if txt != "" {
+			txt += "\n"
 		}
-		md += txt[i]
+		txt += lines[i]
 		count++
 
-		remaining := len(txt) - i
+		remaining := len(lines) - i
 		if (count > maxLines) && (maxLines > 0) && (remaining > maxLines/2) {
-			md += fmt.Sprintf("\n  (skip %d lines)", remaining)
+			txt += fmt.Sprintf("\n(skip %d lines)", remaining)
 			break
 		}
 	}
 
-	return md
+	return txt
 }
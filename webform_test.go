@@ -0,0 +1,54 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/teal-finance/garcon"
+	"github.com/teal-finance/garcon/gg"
+)
+
+func TestWebForm_Notify(t *testing.T) {
+	t.Parallel()
+
+	rec := &messageRecorder{} // see muted-notifier_test.go
+	wf := garcon.NewContactForm("", "/thanks")
+	wf.Notifier = rec
+	handler := wf.Notify("")
+
+	form := url.Values{
+		"name":    {"*Bob* [x](https://evil.example.org)"},
+		"text":    {"\n\nline1\n\n\n\nline2\n"},
+		"unknown": {"skipped"},
+	}
+	r := httptest.NewRequest(http.MethodPost, "/contact", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	handler(w, r)
+
+	if w.Code != http.StatusFound || w.Header().Get("Location") != "/thanks" {
+		t.Fatalf("status=%d Location=%q", w.Code, w.Header().Get("Location"))
+	}
+	if len(rec.structured) != 1 || len(rec.messages) != 0 {
+		t.Fatalf("structured=%+v plain=%q", rec.structured, rec.messages)
+	}
+
+	m := rec.structured[0]
+	want := []gg.Field{{Name: "name", Value: form["name"][0]}, {Name: "text", Value: "line1\n\nline2"}}
+	if len(m.Fields) < 3 || m.Fields[0] != want[0] || m.Fields[1] != want[1] || m.Fields[2].Name != "IP" {
+		t.Errorf("fields=%+v", m.Fields)
+	}
+
+	// the user content is escaped by the notifier markup
+	if md := m.Render(gg.FormatTelegram); !strings.Contains(md, `\*Bob\* \[x\]\(https://evil\.example\.org\)`) {
+		t.Errorf("not escaped: %s", md)
+	}
+}
@@ -143,7 +143,7 @@ func (ws *StaticWebServer) openFile(w http.ResponseWriter, r *http.Request, absP
 	if err != nil {
 		log.Warn("WebServer:", err)
 		http.Error(w, "Not Found", http.StatusNotFound)
-		log.Out("404", r.RemoteAddr, r.Method, absPath, err)
+		log.Out("404", remoteAddr(r), r.Method, absPath, err)
 		return nil, ""
 	}
 
@@ -151,6 +151,11 @@ func (ws *StaticWebServer) openFile(w http.ResponseWriter, r *http.Request, absP
 }
 
 func (ws *StaticWebServer) send(w http.ResponseWriter, r *http.Request, absPath string) {
+	ws.sendStatus(w, r, absPath, http.StatusOK)
+}
+
+// sendStatus is similar to send but responds with the given status code.
+func (ws *StaticWebServer) sendStatus(w http.ResponseWriter, r *http.Request, absPath string, statusCode int) {
 	file, absPath := ws.openFile(w, r, absPath)
 	if file == nil {
 		return
@@ -171,10 +176,14 @@ func (ws *StaticWebServer) send(w http.ResponseWriter, r *http.Request, absPath
 		// to handle the headers Range If-Range Etag and Content-Range.
 	}
 
+	if statusCode != http.StatusOK {
+		w.WriteHeader(statusCode)
+	}
+
 	if n, err := io.Copy(w, file); err != nil {
 		log.Warn("WebServer: Copy("+absPath+")", err)
 	} else {
-		log.Out("200", r.RemoteAddr, r.Method, absPath, gg.ConvertSize64(n))
+		log.Out(StatusCodeStr(statusCode), remoteAddr(r), r.Method, absPath, gg.ConvertSize64(n))
 	}
 }
 
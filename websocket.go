@@ -0,0 +1,367 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+)
+
+// ErrWebSocketOrigin is answered when the "Origin" of the upgrade request is not allowed.
+var ErrWebSocketOrigin = RegisterError(http.StatusForbidden, "websocket_origin",
+	"WebSocket connection from a not allowed origin")
+
+// WSHandler processes a WebSocket connection.
+// The connection is closed when WSHandler returns.
+// The ctx is canceled when the keepalive fails.
+type WSHandler func(ctx context.Context, c *WSConn)
+
+// WebSocket upgrades the HTTP requests to WebSocket connections.
+// The upgrade request must come from an allowed origin (exact scheme and host)
+// and must have a valid token when the Checker is set (see TokenChecker.Vet).
+// The WebSocket pings the clients to detect the dead connections,
+// limits the message size and the received messages rate (per connection).
+//
+// WebSocket also clears the read/write deadlines set by the server (see Garcon.Server):
+// a WebSocket connection can last longer than the WriteTimeout.
+type WebSocket struct {
+	// Writer writes the JSON errors (rejected upgrade).
+	Writer Writer
+
+	// Checker (optional) verifies the token of the upgrade request.
+	Checker TokenChecker
+
+	// RateLimiter (optional) provides the settings (burst and rate)
+	// of the received messages per connection.
+	RateLimiter *ReqLimiter
+
+	// AllowAnyOrigin accepts the upgrade requests from any origin.
+	// Keep it false when the WebSocket authenticates with a cookie:
+	// any web site could open a connection on behalf of the user
+	// (Cross-Site WebSocket Hijacking).
+	AllowAnyOrigin bool
+
+	// Subprotocols lists the supported WebSocket subprotocols in order of preference.
+	Subprotocols []string
+
+	// ReadLimit is the max size in bytes of a received message.
+	// The connection is closed with the status 1009 (message too big) when exceeded.
+	ReadLimit int64
+
+	// PingPeriod is the period of the keepalive ping.
+	// The connection is closed when the pong does not arrive within PingPeriod/2.
+	// Zero disables the keepalive.
+	PingPeriod time.Duration
+
+	handler     WSHandler
+	allowOrigin func(string) bool
+
+	open      atomic.Int64
+	accepted  atomic.Uint64
+	rejected  atomic.Uint64
+	received  atomic.Uint64
+	sent      atomic.Uint64
+	throttled atomic.Uint64
+}
+
+// NewWebSocket creates a WebSocket accepting the upgrade requests from the allowedOrigins.
+// The requests without the "Origin" header (non-browser clients) are always accepted.
+// When allowedOrigins is empty, all the browser origins are rejected (see AllowAnyOrigin).
+// Default settings: 32 KB max message size, ping every 30 seconds, no rate limit.
+func NewWebSocket(gw Writer, allowedOrigins []string, handler WSHandler) *WebSocket {
+	return &WebSocket{
+		Writer:         gw,
+		Checker:        nil,
+		RateLimiter:    nil,
+		AllowAnyOrigin: false,
+		Subprotocols:   nil,
+		ReadLimit:      32 * 1024,
+		PingPeriod:     30 * time.Second,
+		handler:        handler,
+		allowOrigin:    wsOriginFunc(allowedOrigins),
+		open:           atomic.Int64{},
+		accepted:       atomic.Uint64{},
+		rejected:       atomic.Uint64{},
+		received:       atomic.Uint64{},
+		sent:           atomic.Uint64{},
+		throttled:      atomic.Uint64{},
+	}
+}
+
+// NewWebSocket creates a WebSocket accepting the origins of the Garcon URLs
+// and exports its metrics to Prometheus (the name distinguishes several WebSockets).
+// The optional settings limit the received messages per connection,
+// the same as MiddlewareRateLimiter: maxMsgBurst and maxMsgPerMinute.
+func (g *Garcon) NewWebSocket(name string, handler WSHandler, settings ...int) *WebSocket {
+	ws := NewWebSocket(g.Writer, g.allowedOrigins, handler)
+	rl := g.newRateLimiter("NewWebSocket", settings)
+	ws.RateLimiter = &rl
+	prometheus.MustRegister(ws.Collector(g.ServerName, name))
+	return ws
+}
+
+// ServeHTTP verifies the origin and the token (if Checker is set), then upgrades the connection.
+func (ws *WebSocket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// the browsers always send the Origin: prevent Cross-Site WebSocket Hijacking
+	if origin := r.Header.Get("Origin"); origin != "" && !ws.AllowAnyOrigin && !ws.allowOrigin(origin) {
+		ws.rejected.Add(1)
+		ws.Writer.WriteErr(w, r, 0, ErrWebSocketOrigin, "origin", origin)
+		return
+	}
+
+	if ws.Checker != nil {
+		ws.Checker.Vet(http.HandlerFunc(ws.upgrade)).ServeHTTP(w, r)
+		return
+	}
+
+	ws.upgrade(w, r)
+}
+
+// wsOriginFunc compares the scheme and the host (with port) of the origin exactly.
+// The development origins ending with ":" or "." (see DevOrigins)
+// accept only a port number or the last IPv4 byte (and an optional port).
+func wsOriginFunc(allowedOrigins []string) func(string) bool {
+	allowed := make([]string, 0, len(allowedOrigins))
+	for _, o := range allowedOrigins {
+		if !strings.Contains(o, "://") {
+			o = "http://" + o
+		}
+		if sh := schemeHost(o); sh != "" {
+			allowed = append(allowed, sh)
+		}
+	}
+
+	return func(origin string) bool {
+		sh := schemeHost(origin)
+		if sh == "" {
+			return false
+		}
+		for _, a := range allowed {
+			if sh == a {
+				return true
+			}
+			rest, ok := strings.CutPrefix(sh, a)
+			if !ok {
+				continue
+			}
+			switch a[len(a)-1] {
+			case ':':
+				if isDigits(rest) {
+					return true
+				}
+			case '.':
+				host, port, hasPort := strings.Cut(rest, ":")
+				if isDigits(host) && (!hasPort || isDigits(port)) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// schemeHost returns the lower-case "scheme://host:port" of the URL,
+// or an empty string when the URL is not an origin (path, user info, "null"...).
+func schemeHost(origin string) string {
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" || u.User != nil || (u.Path != "" && u.Path != "/") ||
+		u.RawQuery != "" || u.Fragment != "" {
+		return ""
+	}
+	return strings.ToLower(u.Scheme + "://" + u.Host)
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (ws *WebSocket) upgrade(w http.ResponseWriter, r *http.Request) {
+	// the hijacked connection keeps the deadlines of the server
+	rc := http.NewResponseController(w)
+	for _, err := range []error{rc.SetReadDeadline(time.Time{}), rc.SetWriteDeadline(time.Time{})} {
+		if err != nil && !errors.Is(err, http.ErrNotSupported) {
+			log.Warn("WebSocket clear deadline:", err)
+		}
+	}
+
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+		Subprotocols:         ws.Subprotocols,
+		InsecureSkipVerify:   true, // origin already verified
+		OriginPatterns:       nil,
+		CompressionMode:      websocket.CompressionDisabled,
+		CompressionThreshold: 0,
+		OnPingReceived:       nil,
+	})
+	if err != nil {
+		ws.rejected.Add(1)
+		log.Warn("WebSocket upgrade:", err)
+		return // Accept has already written the error response
+	}
+	conn.SetReadLimit(ws.ReadLimit)
+
+	ws.accepted.Add(1)
+	ws.open.Add(1)
+	defer ws.open.Add(-1)
+
+	c := &WSConn{
+		conn:    conn,
+		ws:      ws,
+		request: r,
+		limiter: nil,
+	}
+	if ws.RateLimiter != nil {
+		c.limiter = ws.RateLimiter.NewLimiter()
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	if ws.PingPeriod > 0 {
+		go c.keepalive(ctx, cancel)
+	}
+
+	ws.handler(ctx, c)
+	conn.Close(websocket.StatusNormalClosure, "")
+}
+
+// WSConn is a WebSocket connection counting the messages for the metrics.
+type WSConn struct {
+	conn    *websocket.Conn
+	ws      *WebSocket
+	request *http.Request
+	limiter *rate.Limiter
+}
+
+// Request returns the upgrade request (e.g. to get the token from its context).
+func (c *WSConn) Request() *http.Request { return c.request }
+
+// Subprotocol returns the negotiated subprotocol.
+func (c *WSConn) Subprotocol() string { return c.conn.Subprotocol() }
+
+// Read reads the next message.
+// Read delays the message when the client exceeds the rate limit (backpressure).
+// The handler must keep reading: the pong messages are processed by Read.
+func (c *WSConn) Read(ctx context.Context) (websocket.MessageType, []byte, error) {
+	typ, p, err := c.conn.Read(ctx)
+	if err != nil {
+		return typ, p, err
+	}
+	c.ws.received.Add(1)
+
+	if c.limiter != nil && !c.limiter.Allow() {
+		c.ws.throttled.Add(1)
+		if err = c.limiter.Wait(ctx); err != nil {
+			return 0, nil, err
+		}
+	}
+	return typ, p, nil
+}
+
+// Write sends a message.
+func (c *WSConn) Write(ctx context.Context, typ websocket.MessageType, p []byte) error {
+	err := c.conn.Write(ctx, typ, p)
+	if err == nil {
+		c.ws.sent.Add(1)
+	}
+	return err
+}
+
+// Close closes the connection with the WebSocket status code and reason.
+func (c *WSConn) Close(code websocket.StatusCode, reason string) error {
+	return c.conn.Close(code, reason)
+}
+
+// keepalive closes the connection when the client does not answer the ping.
+func (c *WSConn) keepalive(ctx context.Context, cancel context.CancelFunc) {
+	ticker := time.NewTicker(c.ws.PingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pingCtx, pingCancel := context.WithTimeout(ctx, c.ws.PingPeriod/2)
+			err := c.conn.Ping(pingCtx)
+			pingCancel()
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Info("WebSocket keepalive:", err, ipMethodURLSafe(c.request))
+					c.conn.CloseNow()
+				}
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// --------------------------------------
+// Prometheus metrics
+
+type webSocketCollector struct {
+	ws        *WebSocket
+	open      *prometheus.Desc
+	accepted  *prometheus.Desc
+	rejected  *prometheus.Desc
+	received  *prometheus.Desc
+	sent      *prometheus.Desc
+	throttled *prometheus.Desc
+}
+
+// Collector returns a prometheus.Collector exporting the open connections,
+// the accepted/rejected upgrades and the received/sent/throttled messages.
+func (ws *WebSocket) Collector(namespace ServerName, name string) prometheus.Collector {
+	labels := prometheus.Labels{"websocket": name}
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(string(namespace), "websocket", metric), help, nil, labels)
+	}
+
+	return &webSocketCollector{
+		ws:        ws,
+		open:      desc("open_connections", "Number of open WebSocket connections."),
+		accepted:  desc("accepted_total", "Number of accepted upgrade requests."),
+		rejected:  desc("rejected_total", "Number of rejected upgrade requests (origin or handshake)."),
+		received:  desc("received_messages_total", "Number of received messages."),
+		sent:      desc("sent_messages_total", "Number of sent messages."),
+		throttled: desc("throttled_messages_total", "Number of received messages delayed by the rate limit."),
+	}
+}
+
+func (c *webSocketCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.open
+	ch <- c.accepted
+	ch <- c.rejected
+	ch <- c.received
+	ch <- c.sent
+	ch <- c.throttled
+}
+
+func (c *webSocketCollector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(c.open, prometheus.GaugeValue, float64(c.ws.open.Load()))
+	ch <- prometheus.MustNewConstMetric(c.accepted, prometheus.CounterValue, float64(c.ws.accepted.Load()))
+	ch <- prometheus.MustNewConstMetric(c.rejected, prometheus.CounterValue, float64(c.ws.rejected.Load()))
+	ch <- prometheus.MustNewConstMetric(c.received, prometheus.CounterValue, float64(c.ws.received.Load()))
+	ch <- prometheus.MustNewConstMetric(c.sent, prometheus.CounterValue, float64(c.ws.sent.Load()))
+	ch <- prometheus.MustNewConstMetric(c.throttled, prometheus.CounterValue, float64(c.ws.throttled.Load()))
+}
@@ -0,0 +1,278 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/teal-finance/garcon"
+)
+
+// echo sends back the received messages.
+func echo(ctx context.Context, c *garcon.WSConn) {
+	for {
+		typ, p, err := c.Read(ctx)
+		if err != nil {
+			return
+		}
+		if err = c.Write(ctx, typ, p); err != nil {
+			return
+		}
+	}
+}
+
+func dialWS(t *testing.T, url string, header http.Header) (*websocket.Conn, *http.Response, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	url = "ws" + strings.TrimPrefix(url, "http")
+	return websocket.Dial(ctx, url, &websocket.DialOptions{HTTPHeader: header})
+}
+
+func TestWebSocket_Echo(t *testing.T) {
+	t.Parallel()
+
+	ws := garcon.NewWebSocket("", []string{"https://app.example.com"}, echo)
+	srv := httptest.NewServer(ws)
+	t.Cleanup(srv.Close)
+
+	conn, _, err := dialWS(t, srv.URL, http.Header{"Origin": {"https://app.example.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.CloseNow()
+
+	ctx := t.Context()
+	for _, msg := range []string{"hello", "world"} {
+		if err = conn.Write(ctx, websocket.MessageText, []byte(msg)); err != nil {
+			t.Fatal(err)
+		}
+		_, p, err := conn.Read(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(p) != msg {
+			t.Errorf("echo=%q want %q", p, msg)
+		}
+	}
+
+	want := `
+# HELP test_websocket_open_connections Number of open WebSocket connections.
+# TYPE test_websocket_open_connections gauge
+test_websocket_open_connections{websocket="echo"} 1
+# HELP test_websocket_received_messages_total Number of received messages.
+# TYPE test_websocket_received_messages_total counter
+test_websocket_received_messages_total{websocket="echo"} 2
+`
+	c := ws.Collector("test", "echo")
+	if err := testutil.CollectAndCompare(c, strings.NewReader(want),
+		"test_websocket_open_connections", "test_websocket_received_messages_total"); err != nil {
+		t.Error(err)
+	}
+	if n := testutil.CollectAndCount(c); n != 6 {
+		t.Errorf("collected %d metrics, want 6", n)
+	}
+}
+
+func TestWebSocket_Origin(t *testing.T) {
+	t.Parallel()
+
+	ws := garcon.NewWebSocket("", []string{"https://app.example.com"}, echo)
+	srv := httptest.NewServer(ws)
+	t.Cleanup(srv.Close)
+
+	_, resp, err := dialWS(t, srv.URL, http.Header{"Origin": {"https://evil.example.org"}})
+	if err == nil {
+		t.Fatal("want the upgrade rejected")
+	}
+	if resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("want 403, got %v", resp)
+	}
+
+	// non-browser clients do not send the Origin header
+	conn, _, err := dialWS(t, srv.URL, nil)
+	if err != nil {
+		t.Fatal("without Origin:", err)
+	}
+	conn.CloseNow()
+}
+
+func TestWebSocket_AllowedOrigins(t *testing.T) {
+	t.Parallel()
+
+	allowed := []string{"https://app.example.com", "https://admin.example.com:8443", "http://localhost:", "http://192.168.1."}
+
+	cases := []struct {
+		origin  string
+		allowed bool
+	}{
+		{"https://app.example.com", true},
+		{"HTTPS://App.Example.com", true},
+		{"https://admin.example.com:8443", true},
+		{"http://localhost:3000", true},
+		{"http://192.168.1.12:8080", true},
+		{"https://app.example.com.evil.net", false},
+		{"https://app.example.com:444", false},
+		{"http://app.example.com", false},
+		{"https://admin.example.com", false},
+		{"https://app.example.com@evil.net", false},
+		{"http://localhost:.evil.net", false},
+		{"http://192.168.1.12.evil.net", false},
+		{"null", false},
+	}
+
+	ws := garcon.NewWebSocket("", allowed, echo)
+	for _, c := range cases {
+		r := httptest.NewRequest(http.MethodGet, "/ws", nil)
+		r.Header.Set("Origin", c.origin)
+		w := httptest.NewRecorder()
+		ws.ServeHTTP(w, r) // not an upgrade request: 426 when the origin is allowed
+
+		if rejected := w.Code == http.StatusForbidden; rejected == c.allowed {
+			t.Errorf("%s: status=%d want allowed=%v", c.origin, w.Code, c.allowed)
+		}
+	}
+}
+
+func TestWebSocket_NoAllowedOrigin(t *testing.T) {
+	t.Parallel()
+
+	ws := garcon.NewWebSocket("", nil, echo)
+
+	for _, anyOrigin := range []bool{false, true} {
+		ws.AllowAnyOrigin = anyOrigin
+		r := httptest.NewRequest(http.MethodGet, "/ws", nil)
+		r.Header.Set("Origin", "https://evil.example.org")
+		w := httptest.NewRecorder()
+		ws.ServeHTTP(w, r)
+
+		if rejected := w.Code == http.StatusForbidden; rejected == anyOrigin {
+			t.Errorf("AllowAnyOrigin=%v: status=%d", anyOrigin, w.Code)
+		}
+	}
+}
+
+func TestWebSocket_TokenChecker(t *testing.T) {
+	t.Parallel()
+
+	ws := garcon.NewWebSocket("", nil, echo)
+	ws.Checker = headerChecker{} // see sse_test.go
+	srv := httptest.NewServer(ws)
+	t.Cleanup(srv.Close)
+
+	_, resp, err := dialWS(t, srv.URL, http.Header{"Token": {"bad"}})
+	if err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("want 401, got err=%v resp=%v", err, resp)
+	}
+
+	conn, _, err := dialWS(t, srv.URL, http.Header{"Token": {"ok"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.CloseNow()
+}
+
+func TestWebSocket_ReadLimit(t *testing.T) {
+	t.Parallel()
+
+	ws := garcon.NewWebSocket("", nil, echo)
+	ws.ReadLimit = 8
+	srv := httptest.NewServer(ws)
+	t.Cleanup(srv.Close)
+
+	conn, _, err := dialWS(t, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.CloseNow()
+
+	ctx := t.Context()
+	if err = conn.Write(ctx, websocket.MessageText, []byte("this message is too big")); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = conn.Read(ctx)
+	if websocket.CloseStatus(err) != websocket.StatusMessageTooBig {
+		t.Errorf("want close status 1009, got %v", err)
+	}
+}
+
+func TestWebSocket_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	rl := garcon.NewRateLimiter("", 2, 60, false) // burst=2 then 1 message per second
+	ws := garcon.NewWebSocket("", nil, echo)
+	ws.RateLimiter = &rl
+	srv := httptest.NewServer(ws)
+	t.Cleanup(srv.Close)
+
+	conn, _, err := dialWS(t, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.CloseNow()
+
+	ctx := t.Context()
+	start := time.Now()
+	for range 3 {
+		if err = conn.Write(ctx, websocket.MessageText, []byte("x")); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err = conn.Read(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d := time.Since(start); d < 500*time.Millisecond {
+		t.Errorf("third message not delayed by the rate limit (%v)", d)
+	}
+
+	want := `
+# HELP test_websocket_throttled_messages_total Number of received messages delayed by the rate limit.
+# TYPE test_websocket_throttled_messages_total counter
+test_websocket_throttled_messages_total{websocket="rl"} 1
+`
+	if err := testutil.CollectAndCompare(ws.Collector("test", "rl"), strings.NewReader(want),
+		"test_websocket_throttled_messages_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWebSocket_Keepalive(t *testing.T) {
+	t.Parallel()
+
+	done := make(chan error, 1)
+	ws := garcon.NewWebSocket("", nil, func(ctx context.Context, c *garcon.WSConn) {
+		_, _, err := c.Read(ctx)
+		done <- err
+	})
+	ws.PingPeriod = 50 * time.Millisecond
+	srv := httptest.NewServer(ws)
+	t.Cleanup(srv.Close)
+
+	conn, _, err := dialWS(t, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.CloseNow()
+
+	// the client does not read: the pings are not answered
+	select {
+	case err := <-done:
+		if err == nil || errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("unexpected error %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("dead connection not closed by the keepalive")
+	}
+}
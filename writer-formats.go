@@ -0,0 +1,358 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/mailru/easyjson"
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/teal-finance/garcon/gg"
+)
+
+// Media types negotiated by Writer.WriteOKFor.
+const (
+	MediaTypeCBOR    = "application/cbor"
+	MediaTypeMsgPack = "application/msgpack"
+	MediaTypeXML     = "application/xml"
+	MediaTypeCSV     = "text/csv"
+)
+
+// ErrNotAcceptable is answered by Writer.WriteOKFor when no format matches the "Accept" header.
+var ErrNotAcceptable = RegisterError(http.StatusNotAcceptable, "not_acceptable",
+	"None of the media types in the Accept header is supported")
+
+// offers returns the media types supported for the key-values, JSON first:
+// XML does not support the maps, CSV requires a slice of structs.
+func offers(kv []any) []string {
+	list := []string{MediaTypeJSON, MediaTypeCBOR, MediaTypeMsgPack, "application/x-msgpack"}
+	if xmlableKV(kv) {
+		list = append(list, MediaTypeXML, "text/xml")
+	}
+	if len(kv) == 1 && csvable(kv[0]) {
+		list = append(list, MediaTypeCSV)
+	}
+	return list
+}
+
+// negotiate returns the media type of the WriteOKFor response,
+// or an empty string when the "Accept" header matches none of the offers.
+// JSON is used when r is nil.
+//
+// JSON is the default whenever acceptable: another format is selected only when
+// the client names it explicitly, as its top preference and with a higher quality than JSON.
+// The browsers send "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+// and receive JSON, not XML.
+func negotiate(w http.ResponseWriter, r *http.Request, kv []any) string {
+	if r == nil {
+		return MediaTypeJSON
+	}
+	addVary(w.Header(), "Accept")
+
+	accept := r.Header.Get("Accept")
+	list := offers(kv)
+	jsonQ := gg.Quality(accept, MediaTypeJSON)
+
+	items := gg.ParseAccept(accept)
+	top := 0.0
+	for _, it := range items {
+		top = max(top, it.Q)
+	}
+	for _, it := range items {
+		if it.Q == top && it.Q > jsonQ && slices.Contains(list, it.Value) {
+			return it.Value
+		}
+	}
+
+	if jsonQ > 0 {
+		return MediaTypeJSON
+	}
+	return gg.Negotiate(accept, list...)
+}
+
+// marshal serializes the key-values in the media type
+// and returns the value of the "Content-Type" header.
+func marshal(mediaType string, kv []any) ([]byte, string, error) {
+	var buf []byte
+	var err error
+
+	switch mediaType {
+	case MediaTypeCBOR:
+		buf, err = cbor.Marshal(kvValue(kv))
+	case MediaTypeMsgPack, "application/x-msgpack":
+		buf, err = marshalMsgPack(kvValue(kv))
+	case MediaTypeXML, "text/xml":
+		buf, err = marshalXML(kv)
+		mediaType += "; charset=utf-8"
+	case MediaTypeCSV:
+		buf, err = marshalCSV(kv[0])
+		mediaType += "; charset=utf-8"
+	default:
+		buf, err = marshalJSON(kv)
+		mediaType = MediaTypeJSON
+	}
+
+	return buf, mediaType, err
+}
+
+// marshalJSON uses the easyjson marshaler when implemented by the value (see version_easyjson.go).
+func marshalJSON(kv []any) ([]byte, error) {
+	switch len(kv) {
+	case 0:
+		return []byte("{}"), nil
+
+	case 1:
+		if m, ok := kv[0].(easyjson.Marshaler); ok {
+			return easyjson.Marshal(m)
+		}
+		return json.Marshal(kv[0])
+
+	default:
+		buf := make([]byte, 0, 1024) // 1024 = max bytes of most of the JSON responses
+		buf = append(buf, '{')
+		buf = appendKeyValues(buf, false, kv)
+		return append(buf, '}'), nil
+	}
+}
+
+// kvValue returns the single value, or the key-values as a map for the binary formats.
+func kvValue(kv []any) any {
+	if len(kv) == 1 {
+		return kv[0]
+	}
+	if len(kv)%2 != 0 {
+		log.Panic("Writer: want non-zero even len(kv) but got", len(kv))
+	}
+
+	m := make(map[string]any, len(kv)/2)
+	for i := 0; i < len(kv); i += 2 {
+		v := kv[i+1]
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		m[fmt.Sprint(kv[i])] = v
+	}
+	return m
+}
+
+// marshalMsgPack uses the "json" struct tags to keep the same field names as JSON.
+func marshalMsgPack(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	err := enc.Encode(v)
+	return buf.Bytes(), err
+}
+
+// marshalXML wraps the slices and the key-values within a <response> root element.
+func marshalXML(kv []any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+
+	if len(kv) == 1 {
+		if k := reflect.Indirect(reflect.ValueOf(kv[0])).Kind(); k != reflect.Slice && k != reflect.Array {
+			err := enc.Encode(kv[0])
+			return buf.Bytes(), err
+		}
+	} else if len(kv)%2 != 0 {
+		log.Panic("Writer: want non-zero even len(kv) but got", len(kv))
+	}
+
+	root := xml.StartElement{Name: xml.Name{Space: "", Local: "response"}, Attr: nil}
+	err := enc.EncodeToken(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(kv) == 1 {
+		err = enc.Encode(kv[0])
+	} else {
+		for i := 0; i < len(kv) && err == nil; i += 2 {
+			v := kv[i+1]
+			if e, ok := v.(error); ok {
+				v = e.Error()
+			}
+			start := xml.StartElement{Name: xml.Name{Space: "", Local: fmt.Sprint(kv[i])}, Attr: nil}
+			err = enc.EncodeElement(v, start)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = enc.EncodeToken(root.End()); err != nil {
+		return nil, err
+	}
+	err = enc.Flush()
+	return buf.Bytes(), err
+}
+
+// xmlableKV reports whether encoding/xml supports the single value or the values of the key-values.
+func xmlableKV(kv []any) bool {
+	if len(kv) == 1 {
+		return xmlable(reflect.TypeOf(kv[0]), nil)
+	}
+	for i := 1; i < len(kv); i += 2 {
+		if _, ok := kv[i].(error); ok {
+			continue // marshaled as a string
+		}
+		if !xmlable(reflect.TypeOf(kv[i]), nil) {
+			return false
+		}
+	}
+	return true
+}
+
+// xmlable reports whether encoding/xml supports the type:
+// no map, channel, function or complex number, even within the fields.
+// The interfaces are checked at marshaling time (see WriteOKFor).
+func xmlable(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if t == nil || seen[t] {
+		return true
+	}
+	if t.Implements(xmlMarshalerType) || reflect.PointerTo(t).Implements(xmlMarshalerType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer,
+		reflect.Complex64, reflect.Complex128:
+		return false
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return xmlable(t.Elem(), seen)
+	case reflect.Struct:
+		if seen == nil {
+			seen = make(map[reflect.Type]bool)
+		}
+		seen[t] = true // recursive types
+		for i := range t.NumField() {
+			f := t.Field(i)
+			if !f.IsExported() || f.Tag.Get("xml") == "-" {
+				continue
+			}
+			if !xmlable(f.Type, seen) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+var xmlMarshalerType = reflect.TypeFor[xml.Marshaler]()
+
+// csvable reports whether v is a slice (or array) of structs (or pointers to structs).
+func csvable(v any) bool {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return false
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return false
+	}
+	t = t.Elem()
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// marshalCSV writes a header line and one line per struct.
+// The column names are taken from the "csv" struct tags, else from the "json" ones,
+// else the field names. The tag "-" skips the field.
+func marshalCSV(v any) ([]byte, error) {
+	rows := reflect.Indirect(reflect.ValueOf(v))
+	t := rows.Type().Elem()
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var fields []int
+	var header []string
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := csvName(f)
+		if name == "-" {
+			continue
+		}
+		fields = append(fields, i)
+		header = append(header, name)
+	}
+
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+	if err := cw.Write(header); err != nil {
+		return nil, err
+	}
+
+	record := make([]string, len(fields))
+	for i := range rows.Len() {
+		row := rows.Index(i)
+		if row.Kind() == reflect.Pointer {
+			if row.IsNil() {
+				continue
+			}
+			row = row.Elem()
+		}
+		for j, f := range fields {
+			record[j] = csvCell(row.Field(f))
+		}
+		if err := cw.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	cw.Flush()
+	return buf.Bytes(), cw.Error()
+}
+
+func csvName(f reflect.StructField) string {
+	for _, key := range []string{"csv", "json"} {
+		if tag, ok := f.Tag.Lookup(key); ok {
+			if name, _, _ := strings.Cut(tag, ","); name != "" {
+				return name
+			}
+		}
+	}
+	return f.Name
+}
+
+func csvCell(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+	}
+	switch val := v.Interface().(type) {
+	case encoding.TextMarshaler:
+		text, err := val.MarshalText()
+		if err != nil {
+			return ""
+		}
+		return string(text)
+	case error:
+		return val.Error()
+	default:
+		return fmt.Sprint(reflect.Indirect(v).Interface())
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/teal-finance/garcon"
+)
+
+type price struct {
+	Time   time.Time `json:"time"`
+	Symbol string    `json:"symbol"`
+	Note   string    `json:"-"`
+	Value  float64   `json:"value"   csv:"price"`
+}
+
+type withMap struct {
+	M map[string]int `json:"m"`
+}
+
+// withAny holds a map encoding/xml cannot marshal: WriteOKFor falls back to JSON.
+type withAny struct {
+	Any any `json:"any"`
+}
+
+func TestWriter_WriteOKFor_Negotiation(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	prices := []price{
+		{at, "BTC", "", 42000.5},
+		{at, "ETH, wrapped", "skipped", 2500},
+	}
+
+	cases := []struct {
+		name        string
+		accept      string
+		kv          []any
+		status      int
+		contentType string
+		body        string // expected body when not empty
+	}{
+		{"default", "", []any{prices}, http.StatusOK, "application/json", ""},
+		{"any", "*/*", []any{prices}, http.StatusOK, "application/json", ""},
+		{"json", "application/json", []any{"k", "v"}, http.StatusOK, "application/json", `{"k":"v"}`},
+		{"cbor", "application/cbor", []any{prices}, http.StatusOK, "application/cbor", ""},
+		{"msgpack", "application/msgpack", []any{prices}, http.StatusOK, "application/msgpack", ""},
+		{"x-msgpack", "application/x-msgpack", []any{"k", "v"}, http.StatusOK, "application/x-msgpack", ""},
+		{
+			"csv", "text/csv, application/json;q=0.5", []any{prices}, http.StatusOK, "text/csv; charset=utf-8",
+			"time,symbol,price\n2026-01-02T03:04:05Z,BTC,42000.5\n2026-01-02T03:04:05Z,\"ETH, wrapped\",2500\n",
+		},
+		{
+			"xml-kv", "application/xml", []any{"name", "maintenance", "on", true}, http.StatusOK, "application/xml; charset=utf-8",
+			`<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<response><name>maintenance</name><on>true</on></response>`,
+		},
+		{"xml-slice", "text/xml", []any{prices}, http.StatusOK, "text/xml; charset=utf-8", ""},
+		{"csv-not-slice", "text/csv", []any{"k", "v"}, http.StatusNotAcceptable, "application/json", ""},
+		{"xml-map", "application/xml", []any{map[string]int{"a": 1}}, http.StatusNotAcceptable, "application/json", ""},
+		{"unsupported", "image/png", []any{prices}, http.StatusNotAcceptable, "application/json", ""},
+		{
+			"browser", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", []any{"a", 1},
+			http.StatusOK, "application/json", `{"a":1}`,
+		},
+		{"xml-top", "application/xml, */*;q=0.8", []any{"a", 1}, http.StatusOK, "application/xml; charset=utf-8", ""},
+		{"xml-map-field", "application/xml", []any{withMap{}}, http.StatusNotAcceptable, "application/json", ""},
+		{"xml-kv-map", "application/xml", []any{"a", map[string]int{"b": 1}}, http.StatusNotAcceptable, "application/json", ""},
+		{"xml-any-map", "application/xml", []any{withAny{map[string]int{"b": 1}}}, http.StatusOK, "application/json", `{"any":{"b":1}}`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/prices", nil)
+			r.Header.Set("Accept", c.accept)
+			w := httptest.NewRecorder()
+			garcon.WriteOKFor(w, r, c.kv...)
+
+			if w.Code != c.status {
+				t.Fatalf("status=%d want %d body=%s", w.Code, c.status, w.Body.String())
+			}
+			if got := w.Header().Get("Content-Type"); got != c.contentType {
+				t.Errorf("Content-Type=%q want %q", got, c.contentType)
+			}
+			if vary := w.Header().Values("Vary"); len(vary) != 1 || vary[0] != "Accept" {
+				t.Errorf("Vary=%q want a single Accept", vary)
+			}
+			if c.body != "" && w.Body.String() != c.body {
+				t.Errorf("body=%q want %q", w.Body.String(), c.body)
+			}
+			if c.status == http.StatusNotAcceptable && !strings.Contains(w.Body.String(), `"not_acceptable"`) {
+				t.Errorf("406 body without code: %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestWriter_WriteOKFor_Binary(t *testing.T) {
+	t.Parallel()
+
+	want := []price{{time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), "BTC", "", 42000.5}}
+
+	for _, accept := range []string{"application/json", "application/cbor", "application/msgpack"} {
+		r := httptest.NewRequest(http.MethodGet, "/prices", nil)
+		r.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		garcon.WriteOKFor(w, r, want)
+
+		var got []price
+		var err error
+		switch accept {
+		case "application/cbor":
+			err = cbor.Unmarshal(w.Body.Bytes(), &got)
+		case "application/msgpack":
+			dec := msgpack.NewDecoder(w.Body)
+			dec.SetCustomStructTag("json")
+			err = dec.Decode(&got)
+		default:
+			err = json.Unmarshal(w.Body.Bytes(), &got)
+		}
+		if err != nil {
+			t.Fatal(accept, err)
+		}
+		if len(got) != 1 || !got[0].Time.Equal(want[0].Time) || got[0].Symbol != "BTC" || got[0].Value != 42000.5 {
+			t.Errorf("%s: got %+v want %+v", accept, got, want)
+		}
+	}
+}
+
+func TestWriteOK_NilRequest(t *testing.T) {
+	t.Parallel()
+
+	w := httptest.NewRecorder()
+	garcon.WriteOK(w, "k", 1)
+
+	if got := w.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type=%q", got)
+	}
+	if w.Body.String() != `{"k":1}` {
+		t.Errorf("body=%q", w.Body.String())
+	}
+}
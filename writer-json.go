@@ -0,0 +1,51 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/mailru/easyjson"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+// WriteJSON writes the successful JSON response without reflection:
+// the value is serialized by its easyjson generated code
+// (see tools/generate.go and version_easyjson.go).
+// The JSON is written through the pooled buffers of easyjson,
+// and the response contains the "Content-Length" header.
+// WriteJSON is the fast path of WriteOK for the hot endpoints
+// (no content negotiation, see WriteOKFor for the other formats).
+func WriteJSON[T easyjson.Marshaler](w http.ResponseWriter, r *http.Request, v T) {
+	jw := jwriter.Writer{}
+	v.MarshalEasyJSON(&jw)
+	Writer("").dumpJSON(w, r, &jw)
+}
+
+// WriteJSON is the same as the function WriteJSON, but using the doc URL of the Writer on error.
+// Go methods cannot have type parameters: v is the easyjson.Marshaler interface.
+func (gw Writer) WriteJSON(w http.ResponseWriter, r *http.Request, v easyjson.Marshaler) {
+	jw := jwriter.Writer{}
+	v.MarshalEasyJSON(&jw)
+	gw.dumpJSON(w, r, &jw)
+}
+
+// dumpJSON writes the serialized JSON, DumpTo recycles the buffers.
+func (gw Writer) dumpJSON(w http.ResponseWriter, r *http.Request, jw *jwriter.Writer) {
+	if jw.Error != nil {
+		gw.WriteErr(w, r, http.StatusInternalServerError,
+			"Cannot serialize success JSON response", "error", jw.Error)
+		return
+	}
+
+	w.Header().Set("Content-Type", MediaTypeJSON)
+	w.Header().Set("Content-Length", strconv.Itoa(jw.Size()))
+	w.WriteHeader(http.StatusOK)
+	if _, err := jw.DumpTo(w); err != nil {
+		log.Warn("WriteJSON:", err)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/mailru/easyjson/jwriter"
+
+	"github.com/teal-finance/garcon"
+)
+
+// quote implements easyjson.Marshaler as the code generated by easyjson.
+type quote struct {
+	Symbol string  `json:"symbol"`
+	Prices []int64 `json:"prices"`
+	Volume float64 `json:"volume"`
+}
+
+func (q quote) MarshalEasyJSON(out *jwriter.Writer) {
+	out.RawString(`{"symbol":`)
+	out.String(q.Symbol)
+	out.RawString(`,"prices":`)
+	out.RawByte('[')
+	for i, p := range q.Prices {
+		if i > 0 {
+			out.RawByte(',')
+		}
+		out.Int64(p)
+	}
+	out.RawByte(']')
+	out.RawString(`,"volume":`)
+	out.Float64(q.Volume)
+	out.RawByte('}')
+}
+
+func newQuote(n int) quote {
+	q := quote{Symbol: "BTC-USDT", Prices: make([]int64, n), Volume: 123.45}
+	for i := range q.Prices {
+		q.Prices[i] = int64(42000 + i)
+	}
+	return q
+}
+
+func TestWriteJSON(t *testing.T) {
+	t.Parallel()
+
+	for _, n := range []int{0, 10, 10_000} { // 10_000 prices exceed the first pooled buffer
+		t.Run(strconv.Itoa(n), func(t *testing.T) {
+			t.Parallel()
+
+			q := newQuote(n)
+			w := httptest.NewRecorder()
+			garcon.WriteJSON(w, nil, q)
+
+			if w.Code != http.StatusOK {
+				t.Fatal("status", w.Code)
+			}
+			if got := w.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type=%q", got)
+			}
+			if got := w.Header().Get("Content-Length"); got != strconv.Itoa(w.Body.Len()) {
+				t.Errorf("Content-Length=%s want %d", got, w.Body.Len())
+			}
+
+			want, err := json.Marshal(q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if w.Body.String() != string(want) {
+				t.Errorf("body differs from encoding/json:\n got %.200s\nwant %.200s", w.Body.String(), want)
+			}
+		})
+	}
+}
+
+func TestWriteOK_EasyJSONValue(t *testing.T) {
+	t.Parallel()
+
+	w := httptest.NewRecorder()
+	garcon.WriteOK(w, "quote", newQuote(2), "n", 2)
+
+	want := `{"quote":{"symbol":"BTC-USDT","prices":[42000,42001],"volume":123.45},` + "\n" + `"n":2}`
+	if w.Body.String() != want {
+		t.Errorf("body=%q want %q", w.Body.String(), want)
+	}
+}
+
+// discardWriter avoids measuring the httptest.ResponseRecorder buffer.
+type discardWriter struct{ h http.Header }
+
+func (d discardWriter) Header() http.Header         { return d.h }
+func (d discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (d discardWriter) WriteHeader(int)             {}
+
+// quoteReflect has the same fields as quote, but without easyjson marshaler.
+type quoteReflect struct {
+	Symbol string  `json:"symbol"`
+	Prices []int64 `json:"prices"`
+	Volume float64 `json:"volume"`
+}
+
+func benchmarkSizes(b *testing.B, bench func(b *testing.B, q quote)) {
+	b.Helper()
+	for _, n := range []int{10, 1000} {
+		q := newQuote(n)
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+			bench(b, q)
+		})
+	}
+}
+
+// BenchmarkWriteOK_Reflection is the WriteOK path using encoding/json.
+func BenchmarkWriteOK_Reflection(b *testing.B) {
+	benchmarkSizes(b, func(b *testing.B, q quote) {
+		v := quoteReflect(q)
+		w := discardWriter{h: http.Header{}}
+		for b.Loop() {
+			garcon.WriteOK(w, v)
+		}
+	})
+}
+
+// BenchmarkWriteOK_EasyJSON is the WriteOK path detecting the easyjson marshaler.
+func BenchmarkWriteOK_EasyJSON(b *testing.B) {
+	benchmarkSizes(b, func(b *testing.B, q quote) {
+		w := discardWriter{h: http.Header{}}
+		for b.Loop() {
+			garcon.WriteOK(w, q)
+		}
+	})
+}
+
+// BenchmarkWriteJSON is the generic path with pooled buffers.
+func BenchmarkWriteJSON(b *testing.B) {
+	benchmarkSizes(b, func(b *testing.B, q quote) {
+		w := discardWriter{h: http.Header{}}
+		for b.Loop() {
+			garcon.WriteJSON(w, nil, q)
+		}
+	})
+}
+
+// BenchmarkWriteOK_KeyValues is the WriteOK path appending a non-primitive value.
+func BenchmarkWriteOK_KeyValues(b *testing.B) {
+	benchmarkSizes(b, func(b *testing.B, q quote) {
+		v := quoteReflect(q)
+		w := discardWriter{h: http.Header{}}
+		b.Run("reflection", func(b *testing.B) {
+			for b.Loop() {
+				garcon.WriteOK(w, "quote", v, "n", 1)
+			}
+		})
+		b.Run("easyjson", func(b *testing.B) {
+			for b.Loop() {
+				garcon.WriteOK(w, "quote", q, "n", 1)
+			}
+		})
+	})
+}
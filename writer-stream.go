@@ -0,0 +1,262 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"iter"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// MediaTypeNDJSON is the media type of the newline-delimited JSON (one JSON value per line).
+const MediaTypeNDJSON = "application/x-ndjson"
+
+const (
+	// streamFlushPeriod is the max delay the client waits for the already serialized items.
+	streamFlushPeriod = 200 * time.Millisecond
+
+	// streamBufferSize is the max amount of serialized items buffered between two flushes.
+	streamBufferSize = 32 * 1024
+
+	// streamWriteTimeout overrides the WriteTimeout of the server while the stream is open:
+	// a stream can exceed the server WriteTimeout, even when waiting for the next item.
+	streamWriteTimeout = time.Minute
+)
+
+// StreamNDJSON writes the items in NDJSON format, one JSON value per line,
+// without buffering the whole payload in memory.
+// The items are flushed periodically (every 200 ms) and the stream stops
+// when the client cancels the request.
+// When the iterator yields an error (or an item cannot be serialized),
+// StreamNDJSON writes a last line containing the error object in the same shape as WriteErr
+// (including the "code" of an APIError) and stops.
+// The gw Writer provides the doc URL of the error object (Go methods cannot have type parameters).
+func StreamNDJSON[T any](gw Writer, w http.ResponseWriter, r *http.Request, items iter.Seq2[T, error]) {
+	streamJSON(gw, w, r, items, false)
+}
+
+// StreamJSONArray is the same as StreamNDJSON but writes a JSON array, one item per line.
+// The error object is the last item of the array.
+func StreamJSONArray[T any](gw Writer, w http.ResponseWriter, r *http.Request, items iter.Seq2[T, error]) {
+	streamJSON(gw, w, r, items, true)
+}
+
+// Items converts an iterator into the iterator expected by StreamNDJSON and StreamJSONArray.
+func Items[T any](seq iter.Seq[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for item := range seq {
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
+
+// ChanItems converts a channel into the iterator expected by StreamNDJSON and StreamJSONArray.
+// The iteration ends when the channel is closed or when the ctx is done
+// (use the request context to stop waiting the channel when the client disconnects).
+func ChanItems[T any](ctx context.Context, ch <-chan T) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case item, ok := <-ch:
+				if !ok || !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// streamer writes the serialized items through a buffer
+// flushed periodically by a background goroutine,
+// even when the iterator is waiting for the next item.
+type streamer struct {
+	rc    *http.ResponseController
+	bw    *bufio.Writer
+	err   error
+	done  chan struct{}
+	wg    sync.WaitGroup
+	mu    sync.Mutex
+	dirty bool
+}
+
+func newStreamer(w http.ResponseWriter) *streamer {
+	s := &streamer{
+		rc:    http.NewResponseController(w),
+		bw:    bufio.NewWriterSize(w, streamBufferSize),
+		err:   nil,
+		done:  make(chan struct{}),
+		wg:    sync.WaitGroup{},
+		mu:    sync.Mutex{},
+		dirty: false,
+	}
+
+	// send the response headers without waiting the first item
+	if err := s.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		s.err = err
+	}
+	s.extendDeadline()
+
+	s.wg.Add(1)
+	go s.flushPeriodically()
+
+	return s
+}
+
+func (s *streamer) flushPeriodically() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(streamFlushPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			switch {
+			case s.dirty:
+				s.flush()
+			case s.err == nil:
+				s.extendDeadline() // idle stream (e.g. ChanItems waiting for the next item)
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// write returns false when the stream is broken (e.g. client disconnected).
+func (s *streamer) write(b ...[]byte) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, p := range b {
+		if s.err == nil {
+			_, s.err = s.bw.Write(p)
+		}
+	}
+	s.dirty = true
+	return s.err == nil
+}
+
+// close stops the background goroutine and flushes the remaining items.
+func (s *streamer) close() error {
+	close(s.done)
+	s.wg.Wait()
+	s.flush()
+	return s.err
+}
+
+// flush sends the buffered items to the client.
+func (s *streamer) flush() {
+	s.dirty = false
+	if s.err != nil {
+		return
+	}
+	if s.err = s.bw.Flush(); s.err != nil {
+		return
+	}
+	if err := s.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		s.err = err
+		return
+	}
+	s.extendDeadline()
+}
+
+func (s *streamer) extendDeadline() {
+	extendWriteDeadline(s.rc)
+}
+
+// extendWriteDeadline lets a long-lived response exceed the WriteTimeout of the server
+// (see Garcon.Server) as long as it progresses.
+func extendWriteDeadline(rc *http.ResponseController) {
+	err := rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Warn("SetWriteDeadline:", err)
+	}
+}
+
+func streamJSON[T any](gw Writer, w http.ResponseWriter, r *http.Request, items iter.Seq2[T, error], array bool) {
+	ctx := context.Background()
+	if r != nil {
+		ctx = r.Context()
+	}
+
+	if array {
+		w.Header().Set("Content-Type", MediaTypeJSON)
+	} else {
+		w.Header().Set("Content-Type", MediaTypeNDJSON)
+	}
+	w.WriteHeader(http.StatusOK)
+
+	s := newStreamer(w)
+	if array {
+		s.write([]byte{'['})
+	}
+
+	n := 0
+	for item, err := range items {
+		if ctx.Err() != nil {
+			break
+		}
+
+		var buf []byte
+		if err == nil {
+			buf, err = marshalJSON([]any{item})
+		}
+		if err != nil {
+			log.Warnf("Stream error after %d items: %v", n, err)
+			buf = gw.streamErr(r, err)
+		}
+
+		var ok bool
+		switch {
+		case !array:
+			ok = s.write(buf, []byte{'\n'})
+		case n > 0:
+			ok = s.write([]byte{',', '\n'}, buf)
+		default:
+			ok = s.write(buf)
+		}
+		n++
+
+		if err != nil || !ok {
+			break
+		}
+	}
+
+	if ctx.Err() == nil && array {
+		s.write([]byte{']', '\n'})
+	}
+
+	if err := errors.Join(ctx.Err(), s.close()); err != nil {
+		log.Infof("Stream stopped after %d items: %v", n, err)
+	}
+}
+
+// streamErr returns the error object of WriteErr on a single line.
+func (gw Writer) streamErr(r *http.Request, err error) []byte {
+	kv, _ := localize(r, []any{err})
+	gw, _, kv = gw.apiError(http.StatusInternalServerError, kv)
+	buf := gw.appendErr(make([]byte, 0, 256), r, kv)
+
+	// the JSON strings are escaped: the remaining line feeds are separators
+	line := buf[:0]
+	for _, c := range buf {
+		if c != '\n' {
+			line = append(line, c)
+		}
+	}
+	return line
+}
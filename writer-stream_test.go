@@ -0,0 +1,270 @@
+// Copyright 2026 Teal.Finance/Garcon contributors
+// This file is part of Teal.Finance/Garcon,
+// an API and website server under the MIT License.
+// SPDX-License-Identifier: MIT
+
+package garcon_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/teal-finance/garcon"
+)
+
+type tick struct {
+	Symbol string `json:"symbol"`
+	Price  int    `json:"price"`
+}
+
+// ticks yields n ticks, then the error (if not nil).
+func ticks(n int, err error) iter.Seq2[tick, error] {
+	return func(yield func(tick, error) bool) {
+		for i := range n {
+			if !yield(tick{"BTC", 42000 + i}, nil) {
+				return
+			}
+		}
+		if err != nil {
+			yield(tick{}, err)
+		}
+	}
+}
+
+func TestStreamNDJSON(t *testing.T) {
+	t.Parallel()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/ticks", nil)
+	garcon.StreamNDJSON(garcon.Writer(""), w, r, ticks(3, nil))
+
+	if got := w.Header().Get("Content-Type"); got != "application/x-ndjson" {
+		t.Errorf("Content-Type=%q", got)
+	}
+	if !w.Flushed {
+		t.Error("stream not flushed")
+	}
+	want := `{"symbol":"BTC","price":42000}` + "\n" +
+		`{"symbol":"BTC","price":42001}` + "\n" +
+		`{"symbol":"BTC","price":42002}` + "\n"
+	if w.Body.String() != want {
+		t.Errorf("body=%q want %q", w.Body.String(), want)
+	}
+}
+
+func TestStreamJSONArray(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		n    int
+		want string
+	}{
+		{"empty", 0, "[]\n"},
+		{"two", 2, `[{"symbol":"BTC","price":42000},` + "\n" + `{"symbol":"BTC","price":42001}]` + "\n"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			garcon.StreamJSONArray(garcon.Writer(""), w, nil, ticks(c.n, nil))
+
+			if w.Body.String() != c.want {
+				t.Errorf("body=%q want %q", w.Body.String(), c.want)
+			}
+			var list []tick
+			if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list) != c.n {
+				t.Errorf("invalid JSON array len=%d err=%v", len(list), err)
+			}
+		})
+	}
+}
+
+func TestStream_ErrorRecord(t *testing.T) {
+	t.Parallel()
+
+	gw := garcon.NewWriter("https://example.com/doc")
+	failure := fmt.Errorf("history: %w", garcon.ErrInternal)
+
+	t.Run("ndjson", func(t *testing.T) {
+		t.Parallel()
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/ticks?from=2026", nil)
+		garcon.StreamNDJSON(gw, w, r, ticks(2, failure))
+
+		lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("want 3 lines, got %q", w.Body.String())
+		}
+
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(lines[2]), &rec); err != nil {
+			t.Fatal("invalid error record:", err, lines[2])
+		}
+		want := map[string]any{
+			"message": "history: Internal Server Error",
+			"code":    "internal_error",
+			"path":    "/ticks",
+			"query":   "from=2026",
+			"doc":     "https://example.com/doc#internal_error",
+		}
+		for k, v := range want {
+			if rec[k] != v {
+				t.Errorf("%s=%v want %v", k, rec[k], v)
+			}
+		}
+	})
+
+	t.Run("array", func(t *testing.T) {
+		t.Parallel()
+
+		w := httptest.NewRecorder()
+		garcon.StreamJSONArray(gw, w, nil, ticks(1, failure))
+
+		var list []map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+			t.Fatal("invalid JSON array:", err, w.Body.String())
+		}
+		if len(list) != 2 || list[1]["code"] != "internal_error" {
+			t.Errorf("want the error record as last item, got %v", list)
+		}
+	})
+
+	t.Run("unserializable", func(t *testing.T) {
+		t.Parallel()
+
+		w := httptest.NewRecorder()
+		seq := garcon.Items(slices.Values([]any{1, func() {}, 3}))
+		garcon.StreamNDJSON(gw, w, nil, seq)
+
+		lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+		if len(lines) != 2 || lines[0] != "1" || !strings.Contains(lines[1], `"message"`) {
+			t.Errorf("want the first item and the error record, got %q", w.Body.String())
+		}
+	})
+}
+
+func TestStream_ClientCancel(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := httptest.NewRequest(http.MethodGet, "/ticks", nil).WithContext(ctx)
+
+	produced := 0
+	endless := func(yield func(int, error) bool) {
+		for i := 0; ; i++ {
+			produced++
+			if i == 5 {
+				cancel()
+			}
+			if !yield(i, nil) {
+				return
+			}
+		}
+	}
+
+	w := httptest.NewRecorder()
+	garcon.StreamJSONArray(garcon.Writer(""), w, r, endless)
+
+	if produced != 6 {
+		t.Errorf("iterator not stopped after the cancellation: produced %d items", produced)
+	}
+	if strings.HasSuffix(w.Body.String(), "]\n") {
+		t.Errorf("a canceled stream should not be terminated: %q", w.Body.String())
+	}
+}
+
+func TestChanItems_Server(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan tick)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		garcon.StreamNDJSON(garcon.Writer(""), w, r, garcon.ChanItems(r.Context(), ch))
+	}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	// the client receives the first item before the end of the stream (periodic flush)
+	go func() { ch <- tick{"ETH", 2500} }()
+	go func() {
+		time.Sleep(time.Second)
+		ch <- tick{"ETH", 2501}
+		close(ch)
+	}()
+
+	lines := bufio.NewScanner(resp.Body)
+	start := time.Now()
+	if !lines.Scan() || lines.Text() != `{"symbol":"ETH","price":2500}` {
+		t.Fatalf("first line %q err=%v", lines.Text(), lines.Err())
+	}
+	if d := time.Since(start); d >= time.Second {
+		t.Errorf("first item received after %v, want before the second item", d)
+	}
+	if !lines.Scan() || lines.Text() != `{"symbol":"ETH","price":2501}` {
+		t.Fatalf("second line %q err=%v", lines.Text(), lines.Err())
+	}
+	if lines.Scan() {
+		t.Errorf("unexpected line %q", lines.Text())
+	}
+}
+
+// deadlineRecorder records the write deadlines set through http.ResponseController.
+type deadlineRecorder struct {
+	*httptest.ResponseRecorder
+	deadlines []time.Time
+	mu        sync.Mutex
+}
+
+func (d *deadlineRecorder) SetWriteDeadline(t time.Time) error {
+	d.mu.Lock()
+	d.deadlines = append(d.deadlines, t)
+	d.mu.Unlock()
+	return nil
+}
+
+func (d *deadlineRecorder) count() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.deadlines)
+}
+
+func TestStream_IdleExtendsDeadline(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan tick)
+	r := httptest.NewRequest(http.MethodGet, "/ticks", nil)
+	w := &deadlineRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+	done := make(chan struct{})
+	go func() {
+		garcon.StreamNDJSON(garcon.Writer(""), w, r, garcon.ChanItems(r.Context(), ch))
+		close(done)
+	}()
+
+	// no item during one second: the deadline is extended while the stream is idle
+	time.Sleep(time.Second)
+	if n := w.count(); n < 3 {
+		t.Errorf("the write deadline has been extended %d times while idle, want at least 3", n)
+	}
+
+	close(ch)
+	<-done
+}
@@ -13,6 +13,9 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/mailru/easyjson"
+	"github.com/mailru/easyjson/jwriter"
+
 	"github.com/teal-finance/garcon/gg"
 )
 
@@ -38,11 +41,11 @@ func InvalidPath(w http.ResponseWriter, r *http.Request) {
 }
 
 func (gw Writer) NotImplemented(w http.ResponseWriter, r *http.Request) {
-	gw.WriteErr(w, r, http.StatusNotImplemented, pathReserved)
+	gw.WriteErr(w, r, http.StatusNotImplemented, ErrPathReserved)
 }
 
 func (gw Writer) InvalidPath(w http.ResponseWriter, r *http.Request) {
-	gw.WriteErr(w, r, http.StatusBadRequest, pathInvalid)
+	gw.WriteErr(w, r, http.StatusBadRequest, ErrPathInvalid)
 }
 
 func WriteErr(w http.ResponseWriter, r *http.Request, statusCode int, kv ...any) {
@@ -53,20 +56,67 @@ func WriteOK(w http.ResponseWriter, kv ...any) {
 	Writer("").WriteOK(w, kv...)
 }
 
+func WriteOKFor(w http.ResponseWriter, r *http.Request, kv ...any) {
+	Writer("").WriteOKFor(w, r, kv...)
+}
+
 // TraversalPath returns true when path contains ".." to prevent path traversal attack.
 func (gw Writer) TraversalPath(w http.ResponseWriter, r *http.Request) bool {
 	if strings.Contains(r.URL.Path, "..") {
-		gw.WriteErr(w, r, http.StatusBadRequest, "URL contains '..'")
+		gw.WriteErr(w, r, http.StatusBadRequest, ErrPathTraversal)
 		log.Warn("reject path with '..'", gg.Sanitize(r.URL.Path))
 		return true
 	}
 	return false
 }
 
+// addVary adds the request header name to the "Vary" response header, unless already present.
+func addVary(h http.Header, name string) {
+	for _, v := range h.Values("Vary") {
+		for field := range strings.SplitSeq(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(field), name) {
+				return
+			}
+		}
+	}
+	h.Add("Vary", name)
+}
+
 // WriteErr is a fast pretty-JSON marshaler dedicated to the HTTP error response.
 // WriteErr extends the JSON content when more than two key-values (kv) are provided.
+// When the first kv is an APIError (see RegisterError), the response contains its code,
+// and its HTTP status is used when statusCode is zero.
+// WriteErr translates the message when the localization is enabled (see Locales.Middleware).
+// WriteErr writes the Problem Details format (RFC 9457) when preferred by the "Accept" header
+// (see MiddlewareProblemDetails to make it the default format).
 func (gw Writer) WriteErr(w http.ResponseWriter, r *http.Request, statusCode int, kv ...any) {
-	buf := make([]byte, 0, 1024)
+	kv, lang := localize(r, kv)
+	gw, statusCode, kv = gw.apiError(statusCode, kv)
+
+	if lang != "" {
+		w.Header().Set("Content-Language", lang)
+	}
+	if r != nil {
+		addVary(w.Header(), "Accept")
+		if LanguageFromCtx(r.Context()) != "" {
+			addVary(w.Header(), "Accept-Language")
+		}
+		if wantsProblem(r) {
+			gw.writeProblem(w, r, statusCode, kv...)
+			return
+		}
+	}
+
+	buf := gw.appendErr(make([]byte, 0, 1024), r, kv)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	w.Write(buf)
+}
+
+// appendErr appends the JSON error object: the message, the key-values,
+// the request path and IDs (if r is not nil) and the doc URL.
+func (gw Writer) appendErr(buf []byte, r *http.Request, kv []any) []byte {
 	buf = append(buf, '{')
 
 	buf, comma := appendMessages(buf, kv)
@@ -76,6 +126,7 @@ func (gw Writer) WriteErr(w http.ResponseWriter, r *http.Request, statusCode int
 			buf = append(buf, ',', '\n')
 		}
 		buf = appendURL(buf, r.URL)
+		buf = appendIDs(buf, r)
 		comma = true
 	}
 
@@ -86,38 +137,45 @@ func (gw Writer) WriteErr(w http.ResponseWriter, r *http.Request, statusCode int
 		buf = gw.appendDoc(buf)
 	}
 
-	buf = append(buf, '}')
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	w.Write(buf)
+	return append(buf, '}')
 }
 
 // WriteOK is a fast pretty-JSON marshaler dedicated to the HTTP successful response.
+// WriteOK marshals a single value (one kv), or an object from the key-values,
+// using easyjson when implemented by the value.
 func (gw Writer) WriteOK(w http.ResponseWriter, kv ...any) {
-	var buf []byte
-	var err error
-
-	switch {
-	case len(kv) == 0:
-		buf = []byte("{}")
-
-	case len(kv) == 1:
-		buf, err = json.Marshal(kv[0])
-		if err != nil {
-			gw.WriteErr(w, nil, http.StatusInternalServerError,
-				"Cannot serialize success JSON response", "error", err)
-			return
-		}
+	gw.WriteOKFor(w, nil, kv...)
+}
 
-	default:
-		buf = make([]byte, 0, 1024) // 1024 = max bytes of most of the JSON responses
-		buf = append(buf, '{')
-		buf = appendKeyValues(buf, false, kv)
-		buf = append(buf, '}')
+// WriteOKFor is WriteOK with the format negotiated from the "Accept" header (JSON when r is nil):
+// JSON (the default whenever acceptable), CBOR, MessagePack,
+// XML (except maps) and CSV (only a slice of structs).
+// WriteOKFor answers "406 Not Acceptable" (see ErrNotAcceptable) when no format is acceptable.
+// WriteOKFor sets the strong ETag of the body and answers "304 Not Modified"
+// to the conditional GET: If-None-Match, or If-Modified-Since (see LastModified).
+func (gw Writer) WriteOKFor(w http.ResponseWriter, r *http.Request, kv ...any) {
+	mediaType := negotiate(w, r, kv)
+	if mediaType == "" {
+		gw.WriteErr(w, r, 0, ErrNotAcceptable, "supported", offers(kv))
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
+	buf, contentType, err := marshal(mediaType, kv)
+	if err != nil && (mediaType == MediaTypeXML || mediaType == "text/xml") {
+		// interface values not supported by encoding/xml (see xmlable)
+		buf, contentType, err = marshal(MediaTypeJSON, kv)
+	}
+	if err != nil {
+		gw.WriteErr(w, r, http.StatusInternalServerError,
+			"Cannot serialize success response", "error", err)
+		return
+	}
+
+	if r != nil && notModified(w, r, buf) {
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(http.StatusOK)
 	w.Write(buf)
 }
@@ -208,7 +266,19 @@ func appendValue(buf []byte, a any) []byte {
 	}
 }
 
+// appendJSON uses the easyjson marshaler when implemented (no reflection).
 func appendJSON(buf []byte, obj any) []byte {
+	if m, ok := obj.(easyjson.Marshaler); ok {
+		jw := jwriter.Writer{}
+		m.MarshalEasyJSON(&jw)
+		if jw.Error == nil {
+			b, _ := jw.BuildBytes()
+			return append(buf, b...)
+		}
+		log.Errorf("Writer easyjson %+v %v", obj, jw.Error)
+		return buf
+	}
+
 	b, err := json.Marshal(obj)
 	if err != nil {
 		log.Errorf("Writer jsonify %+v %v", obj, err)
@@ -237,6 +307,22 @@ func appendURL(buf []byte, u *url.URL) []byte {
 	return buf
 }
 
+// appendIDs appends the request ID and the trace ID (if any).
+// Both IDs are safe: they do not need to be escaped.
+func appendIDs(buf []byte, r *http.Request) []byte {
+	if id := gg.RequestIDFromCtx(r.Context()); id != "" {
+		buf = append(buf, []byte(",\n"+`"request_id":"`)...)
+		buf = append(buf, id...)
+		buf = append(buf, '"')
+	}
+	if id := TraceIDFromCtx(r.Context()); id != "" {
+		buf = append(buf, []byte(",\n"+`"trace_id":"`)...)
+		buf = append(buf, id...)
+		buf = append(buf, '"')
+	}
+	return buf
+}
+
 func (gw Writer) appendDoc(buf []byte) []byte {
 	buf = append(buf, '"', 'd', 'o', 'c', '"', ':', '"')
 	buf = append(buf, []byte(string(gw))...)